@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/i18n"
+)
+
+// encoding/json 对未知字段没有专门的错误类型，只能按错误信息识别
+const unknownFieldPrefix = "json: unknown field "
+
+// bindError 将请求绑定错误转换为业务错误：校验失败、未知字段和类型错误附带字段详情，
+// 请求体超限为 payload_too_large，其他错误（如 JSON 格式错误）为 invalid_request。
+// 字段消息为英文，由 ErrorMiddleware 按请求语言翻译
+func bindError(err error) error {
+	var (
+		validationErrors validator.ValidationErrors
+		maxBytesErr      *http.MaxBytesError
+		typeErr          *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &validationErrors):
+		fields := make([]domain.FieldError, len(validationErrors))
+		for i, fe := range validationErrors {
+			fields[i] = fieldError(fe.Field(), fe.Tag(), fe.Param())
+		}
+		return domain.ErrValidation.WithFields(fields...)
+	case errors.As(err, &maxBytesErr):
+		return domain.ErrPayloadTooLarge.Wrap(err)
+	case errors.As(err, &typeErr):
+		return domain.ErrValidation.WithFields(fieldError(typeErr.Field, "type", "")).Wrap(err)
+	case strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		field, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), unknownFieldPrefix))
+		if unquoteErr == nil {
+			return domain.ErrValidation.WithFields(fieldError(field, "unknown", "")).Wrap(err)
+		}
+	}
+	return domain.ErrInvalidRequest.Wrap(err)
+}
+
+func fieldError(field, rule, param string) domain.FieldError {
+	msg, ok := i18n.FieldMessage(i18n.Default, rule, param)
+	if !ok {
+		msg = "is invalid"
+	}
+	return domain.FieldError{Field: field, Rule: rule, Param: param, Message: msg}
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+type HealthController struct {
+	HealthRegistry domain.HealthRegistry
+}
+
+// Liveness godoc
+// @Summary      Liveness probe
+// @Description  Report that the process is alive; does not check dependencies
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  domain.HealthReport
+// @Router       /healthz [get]
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.HealthReport{Status: domain.HealthStatusOK})
+}
+
+// Readiness godoc
+// @Summary      Readiness probe
+// @Description  Check the database, schema migrations and registered dependencies
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  domain.HealthReport
+// @Failure      503  {object}  domain.HealthReport
+// @Router       /readyz [get]
+func (hc *HealthController) Readiness(c *gin.Context) {
+	report := hc.HealthRegistry.Check(c.Request.Context())
+	if report.Status != domain.HealthStatusOK {
+		c.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
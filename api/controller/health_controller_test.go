@@ -0,0 +1,61 @@
+package controller_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/controller"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthController(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dbErr := errors.New("dial tcp db.internal:5432: connection refused")
+	registry := health.NewRegistry(time.Second)
+	registry.Register(health.CheckerFunc("database", func(context.Context) error { return dbErr }))
+
+	hc := &controller.HealthController{HealthRegistry: registry}
+	router := gin.New()
+	router.GET("/healthz", hc.Liveness)
+	router.GET("/readyz", hc.Readiness)
+
+	t.Run("liveness_ignores_dependencies", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("readiness_reports_failing_check", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var report domain.HealthReport
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.Equal(t, domain.HealthStatusFail, report.Status)
+		assert.Equal(t, "unavailable", report.Checks["database"].Error)
+		assert.NotContains(t, w.Body.String(), "db.internal", "does not leak connection details")
+	})
+
+	t.Run("readiness_ok", func(t *testing.T) {
+		okRegistry := health.NewRegistry(time.Second)
+		okRegistry.Register(health.CheckerFunc("database", func(context.Context) error { return nil }))
+		okRouter := gin.New()
+		okRouter.GET("/readyz", (&controller.HealthController{HealthRegistry: okRegistry}).Readiness)
+
+		w := httptest.NewRecorder()
+		okRouter.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
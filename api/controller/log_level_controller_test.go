@@ -0,0 +1,105 @@
+package controller_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/controller"
+	"github.com/horaoen/go-backend-clean-architecture/api/middleware"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/logging"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockLogLevelManager struct {
+	mock.Mock
+}
+
+func (m *MockLogLevelManager) Level() string {
+	return m.Called().String(0)
+}
+
+func (m *MockLogLevelManager) SetLevel(level string) error {
+	return m.Called(level).Error(0)
+}
+
+func TestLogLevelController_Set(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	send := func(lc *controller.LogLevelController, level string) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(middleware.ErrorMiddleware())
+		router.PUT("/admin/log-level", lc.Set)
+		form := url.Values{"level": {level}}
+		req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("success", func(t *testing.T) {
+		manager := new(MockLogLevelManager)
+		manager.On("Level").Return("info").Once()
+		manager.On("SetLevel", "debug").Return(nil)
+		manager.On("Level").Return("debug")
+
+		w := send(&controller.LogLevelController{LogLevelManager: manager}, "debug")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"level":"debug"}`, w.Body.String())
+		manager.AssertExpectations(t)
+	})
+
+	t.Run("invalid_level", func(t *testing.T) {
+		manager := new(MockLogLevelManager)
+		manager.On("Level").Return("info")
+		manager.On("SetLevel", "loud").Return(domain.ErrInvalidLogLevel)
+
+		w := send(&controller.LogLevelController{LogLevelManager: manager}, "loud")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestLogLevelController_Set_AuditLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	original := zerolog.GlobalLevel()
+	t.Cleanup(func() { zerolog.SetGlobalLevel(original) })
+
+	for _, tc := range []struct{ from, to string }{
+		{"info", "error"},
+		{"info", "disabled"},
+		{"disabled", "debug"},
+	} {
+		t.Run(tc.from+"_to_"+tc.to, func(t *testing.T) {
+			manager := logging.NewLevelManager()
+			require.NoError(t, manager.SetLevel(tc.from))
+
+			var buf bytes.Buffer
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(zerolog.New(&buf).WithContext(c.Request.Context()))
+			})
+			router.PUT("/admin/log-level", (&controller.LogLevelController{LogLevelManager: manager}).Set)
+			form := url.Values{"level": {tc.to}}
+			req := httptest.NewRequest(http.MethodPut, "/admin/log-level", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, buf.String(), `"level":"warn"`)
+			assert.Contains(t, buf.String(), `"from":"`+tc.from+`","to":"`+tc.to+`"`)
+			assert.Contains(t, buf.String(), `"message":"log level changed"`)
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,26 +12,34 @@ type LoginController struct {
 	LoginUsecase domain.LoginUsecase
 }
 
+// Login godoc
+// @Summary      Login
+// @Description  Login user with email and password
+// @Tags         Auth
+// @Accept       x-www-form-urlencoded,json
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Param        request  formData  dto.LoginRequest  true  "Login credentials"
+// @Success      200  {object}  dto.LoginResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      403  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /login [post]
 func (lc *LoginController) Login(c *gin.Context) {
 	var request dto.LoginRequest
 
 	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		_ = c.Error(bindError(err))
 		return
 	}
 
 	tokens, err := lc.LoginUsecase.Login(c.Request.Context(), request.Email, request.Password)
 	if err != nil {
-		switch {
-		case errors.Is(err, domain.ErrInvalidCredentials):
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "invalid email or password"})
-		default:
-			c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "internal server error"})
-		}
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.LoginResponse{
+	respond(c, http.StatusOK, dto.LoginResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 	})
@@ -10,11 +10,14 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/horaoen/go-backend-clean-architecture/api/controller"
 	"github.com/horaoen/go-backend-clean-architecture/api/dto"
 	"github.com/horaoen/go-backend-clean-architecture/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type MockLoginUsecase struct {
@@ -53,7 +56,7 @@ func TestLoginController_Login(t *testing.T) {
 
 		mockUsecase.On("Login", mock.Anything, "test@example.com", "password").Return(expectedTokens, nil)
 
-		lc.Login(c)
+		serve(c, lc.Login)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
@@ -85,14 +88,13 @@ func TestLoginController_Login(t *testing.T) {
 
 		mockUsecase.On("Login", mock.Anything, "test@example.com", "wrong_password").Return(domain.TokenPair{}, domain.ErrInvalidCredentials)
 
-		lc.Login(c)
+		serve(c, lc.Login)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "invalid email or password", response.Message)
+		problem := decodeProblem(t, w)
+		assert.Equal(t, string(domain.CodeInvalidCredentials), problem.Code)
+		assert.Equal(t, http.StatusUnauthorized, problem.Status)
 
 		mockUsecase.AssertExpectations(t)
 	})
@@ -114,8 +116,106 @@ func TestLoginController_Login(t *testing.T) {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		c.Request = req
 
-		lc.Login(c)
+		serve(c, lc.Login)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		problem := decodeProblem(t, w)
+		assert.Equal(t, string(domain.CodeValidationFailed), problem.Code)
+		if assert.Len(t, problem.Errors, 1) {
+			assert.Equal(t, "email", problem.Errors[0].Field)
+			assert.Equal(t, "email", problem.Errors[0].Rule)
+		}
+	})
+
+	newJSONContext := func(w *httptest.ResponseRecorder, body string) *gin.Context {
+		c, _ := gin.CreateTestContext(w)
+		req, _ := http.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+		return c
+	}
+
+	t.Run("json_body", func(t *testing.T) {
+		mockUsecase := new(MockLoginUsecase)
+		lc := controller.LoginController{LoginUsecase: mockUsecase}
+		mockUsecase.On("Login", mock.Anything, "test@example.com", "password").Return(expectedTokens, nil)
+
+		w := httptest.NewRecorder()
+		serve(newJSONContext(w, `{"email":"test@example.com","password":"password"}`), lc.Login)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("json_rejected", func(t *testing.T) {
+		tests := map[string]struct {
+			body  string
+			code  domain.ErrorCode
+			field string
+			rule  string
+		}{
+			"unknown_field": {`{"email":"test@example.com","password":"password","admin":true}`, domain.CodeValidationFailed, "admin", "unknown"},
+			"wrong_type":    {`{"email":"test@example.com","password":123}`, domain.CodeValidationFailed, "password", "type"},
+			"malformed":     {`{"email":`, domain.CodeInvalidRequest, "", ""},
+		}
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				lc := controller.LoginController{LoginUsecase: new(MockLoginUsecase)}
+
+				w := httptest.NewRecorder()
+				serve(newJSONContext(w, tt.body), lc.Login)
+
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				problem := decodeProblem(t, w)
+				assert.Equal(t, string(tt.code), problem.Code)
+				if tt.field != "" && assert.Len(t, problem.Errors, 1) {
+					assert.Equal(t, tt.field, problem.Errors[0].Field)
+					assert.Equal(t, tt.rule, problem.Errors[0].Rule)
+				}
+			})
+		}
+	})
+
+	t.Run("body_too_large", func(t *testing.T) {
+		lc := controller.LoginController{LoginUsecase: new(MockLoginUsecase)}
+
+		w := httptest.NewRecorder()
+		c := newJSONContext(w, `{"email":"test@example.com","password":"`+strings.Repeat("x", 64)+`"}`)
+		c.Request.Body = http.MaxBytesReader(w, c.Request.Body, 32)
+		serve(c, lc.Login)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Equal(t, string(domain.CodePayloadTooLarge), decodeProblem(t, w).Code)
+	})
+
+	t.Run("negotiated_formats", func(t *testing.T) {
+		for _, format := range []string{binding.MIMEMSGPACK, binding.MIMEPROTOBUF} {
+			t.Run(format, func(t *testing.T) {
+				mockUsecase := new(MockLoginUsecase)
+				lc := controller.LoginController{LoginUsecase: mockUsecase}
+				mockUsecase.On("Login", mock.Anything, "test@example.com", "password").Return(expectedTokens, nil)
+
+				w := httptest.NewRecorder()
+				c := newJSONContext(w, `{"email":"test@example.com","password":"password"}`)
+				c.Set("x-response-format", format)
+				serve(c, lc.Login)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, format, w.Header().Get("Content-Type"))
+
+				var response dto.LoginResponse
+				if format == binding.MIMEPROTOBUF {
+					var message structpb.Struct
+					require.NoError(t, binding.ProtoBuf.BindBody(w.Body.Bytes(), &message))
+					response.AccessToken = message.Fields["accessToken"].GetStringValue()
+					response.RefreshToken = message.Fields["refreshToken"].GetStringValue()
+				} else {
+					require.NoError(t, binding.MsgPack.BindBody(w.Body.Bytes(), &response))
+				}
+				assert.Equal(t, "access_token", response.AccessToken)
+				assert.Equal(t, "refresh_token", response.RefreshToken)
+			})
+		}
 	})
 }
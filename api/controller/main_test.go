@@ -0,0 +1,33 @@
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/dto"
+	"github.com/horaoen/go-backend-clean-architecture/api/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	dto.ConfigureBinding()
+	os.Exit(m.Run())
+}
+
+// serve 直接调用 handler，并像 ErrorMiddleware 一样渲染其记录的错误
+func serve(c *gin.Context, handler gin.HandlerFunc) {
+	handler(c)
+	middleware.RenderErrors(c)
+}
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) dto.Problem {
+	t.Helper()
+	require.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var problem dto.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	return problem
+}
@@ -2,6 +2,8 @@ package controller
 
 import (
 	"errors"
+	"html/template"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,44 +11,381 @@ import (
 	"github.com/horaoen/go-backend-clean-architecture/domain"
 )
 
+// allowedAvatarTypes 为按内容嗅探得到的可接受头像类型
+var allowedAvatarTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// confirmEmailPage 为确认链接打开的页面，由用户点击按钮以 POST 提交 token
+var confirmEmailPage = template.Must(template.New("confirm-email").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Confirm your new email address</title>
+</head>
+<body>
+<form method="post">
+<input type="hidden" name="token" value="{{.Token}}">
+<p>Confirm that you want to use this address for your account.</p>
+<button type="submit">Confirm email change</button>
+</form>
+</body>
+</html>
+`))
+
+var (
+	errAvatarRequired = domain.ErrValidation.WithFields(domain.FieldError{Field: "avatar", Rule: "required", Message: "is required"})
+	errAvatarTooLarge = domain.ErrPayloadTooLarge.WithMessage("avatar file too large")
+)
+
 type ProfileController struct {
 	ProfileUsecase domain.ProfileUsecase
+	AvatarMaxBytes int64
 }
 
+// Fetch godoc
+// @Summary      Get Profile
+// @Description  Get user profile
+// @Tags         Profile
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Security     BearerAuth
+// @Success      200  {object}  dto.ProfileResponse
+// @Failure      404  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /profile [get]
 func (pc *ProfileController) Fetch(c *gin.Context) {
 	userID := c.GetString("x-user-id")
 
-	profile, err := pc.ProfileUsecase.GetProfileByID(c, userID)
+	// token 仍有效但账号已被删除时返回 404
+	profile, err := pc.ProfileUsecase.GetProfileByID(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.ProfileResponse{
-		Name:  profile.Name,
-		Email: profile.Email,
-	})
+	respond(c, http.StatusOK, newProfileResponse(profile))
 }
 
+// ChangePassword godoc
+// @Summary      Change Password
+// @Description  Change the password of the current user
+// @Tags         Profile
+// @Accept       x-www-form-urlencoded,json
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Security     BearerAuth
+// @Param        request  formData  dto.ChangePasswordRequest  true  "Old and new password"
+// @Success      200  {object}  domain.SuccessResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /profile/change-password [post]
 func (pc *ProfileController) ChangePassword(c *gin.Context) {
 	var request dto.ChangePasswordRequest
 
 	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	userID := c.GetString("x-user-id")
+
+	if err := pc.ProfileUsecase.ChangePassword(c.Request.Context(), userID, request.OldPassword, request.NewPassword); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, domain.SuccessResponse{Message: "Password changed successfully"})
+}
+
+// DeleteAccount godoc
+// @Summary      Delete Account
+// @Description  Soft delete the current user after password re-confirmation. Data is permanently removed after the retention period.
+// @Tags         Profile
+// @Accept       json
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Security     BearerAuth
+// @Param        request  body  dto.DeleteAccountRequest  true  "Password confirmation"
+// @Success      200  {object}  domain.SuccessResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /profile [delete]
+func (pc *ProfileController) DeleteAccount(c *gin.Context) {
+	var request dto.DeleteAccountRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	userID := c.GetString("x-user-id")
+
+	if err := pc.ProfileUsecase.DeleteAccount(c.Request.Context(), userID, request.Password); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, domain.SuccessResponse{Message: "Account deleted successfully"})
+}
+
+// Export godoc
+// @Summary      Export Profile Data
+// @Description  Download a JSON archive of all data stored about the current user
+// @Tags         Profile
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.ProfileExportResponse
+// @Failure      404  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /profile/export [get]
+func (pc *ProfileController) Export(c *gin.Context) {
+	userID := c.GetString("x-user-id")
+
+	export, err := pc.ProfileUsecase.ExportData(c.Request.Context(), userID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="profile-export.json"`)
+	c.JSON(http.StatusOK, dto.ProfileExportResponse{
+		ID:         export.ID,
+		Name:       export.Name,
+		Email:      export.Email,
+		AvatarURL:  export.AvatarURL,
+		Attributes: newProfileAttributes(export.Attributes),
+		Role:       export.Role,
+		DisabledAt: export.DisabledAt,
+		CreatedAt:  export.CreatedAt,
+		UpdatedAt:  export.UpdatedAt,
+		ExportedAt: export.ExportedAt,
+	})
+}
+
+// Update godoc
+// @Summary      Update Profile
+// @Description  Partially update name and profile attributes. Omitted fields are unchanged, empty strings clear an attribute and metadata keys set to null are removed.
+// @Tags         Profile
+// @Accept       json
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Security     BearerAuth
+// @Param        request  body  dto.UpdateProfileRequest  true  "Fields to update"
+// @Success      200  {object}  dto.ProfileResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /profile [patch]
+func (pc *ProfileController) Update(c *gin.Context) {
+	var request dto.UpdateProfileRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		_ = c.Error(bindError(err))
 		return
 	}
 
 	userID := c.GetString("x-user-id")
 
-	err := pc.ProfileUsecase.ChangePassword(c, userID, request.OldPassword, request.NewPassword)
+	profile, err := pc.ProfileUsecase.UpdateProfile(c.Request.Context(), userID, domain.ProfileUpdate{
+		Name: request.Name,
+		Attributes: domain.ProfileAttributesUpdate{
+			DisplayName: request.DisplayName,
+			Locale:      request.Locale,
+			Timezone:    request.Timezone,
+			Phone:       request.Phone,
+			Metadata:    request.Metadata,
+		},
+	})
 	if err != nil {
-		if errors.Is(err, domain.ErrInvalidCredentials) || err.Error() == "invalid old password" {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "invalid old password"})
+		_ = c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, newProfileResponse(profile))
+}
+
+// RequestEmailChange godoc
+// @Summary      Request Email Change
+// @Description  Send a confirmation link to the new address and notify the current address
+// @Tags         Profile
+// @Accept       x-www-form-urlencoded,json
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Security     BearerAuth
+// @Param        request  formData  dto.ChangeEmailRequest  true  "Password and new email"
+// @Success      202  {object}  domain.SuccessResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      409  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /profile/email [post]
+func (pc *ProfileController) RequestEmailChange(c *gin.Context) {
+	var request dto.ChangeEmailRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	userID := c.GetString("x-user-id")
+
+	err := pc.ProfileUsecase.RequestEmailChange(c.Request.Context(), userID, request.Password, request.NewEmail)
+	if err != nil {
+		_ = c.Error(emailInUse(err))
+		return
+	}
+
+	respond(c, http.StatusAccepted, domain.SuccessResponse{Message: "Confirmation email sent to the new address"})
+}
+
+// ConfirmEmailChangePage godoc
+// @Summary      Email Change Confirmation Page
+// @Description  Target of the confirmation link. Renders a page that submits the token with POST; opening the link does not change anything, so mail scanners and link prefetchers cannot confirm the change.
+// @Tags         Profile
+// @Produce      html
+// @Param        token  query  string  true  "Email change token"
+// @Success      200  {string}  string  "HTML page"
+// @Failure      400  {object}  dto.Problem
+// @Router       /profile/email/confirm [get]
+func (pc *ProfileController) ConfirmEmailChangePage(c *gin.Context) {
+	var request dto.ConfirmEmailChangeRequest
+
+	if err := c.ShouldBindQuery(&request); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	// 页面含 token：禁止缓存，也不通过 Referer 泄露给页面引用的其他地址
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	if err := confirmEmailPage.Execute(c.Writer, request); err != nil {
+		_ = c.Error(domain.ErrInternalServer.Wrap(err))
+	}
+}
+
+// ConfirmEmailChange godoc
+// @Summary      Confirm Email Change
+// @Description  Apply an email change using the token from the confirmation link
+// @Tags         Profile
+// @Accept       x-www-form-urlencoded,json
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Param        request  formData  dto.ConfirmEmailChangeRequest  true  "Email change token"
+// @Success      200  {object}  domain.SuccessResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      409  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /profile/email/confirm [post]
+func (pc *ProfileController) ConfirmEmailChange(c *gin.Context) {
+	var request dto.ConfirmEmailChangeRequest
+
+	if err := c.ShouldBind(&request); err != nil {
+		_ = c.Error(bindError(err))
+		return
+	}
+
+	err := pc.ProfileUsecase.ConfirmEmailChange(c.Request.Context(), request.Token)
+	if err != nil {
+		// 确认链接不携带 Authorization，token 无效属于请求参数错误
+		if errors.Is(err, domain.ErrInvalidToken) {
+			err = domain.AsError(err).WithStatus(http.StatusBadRequest)
+		}
+		_ = c.Error(emailInUse(err))
+		return
+	}
+
+	respond(c, http.StatusOK, domain.SuccessResponse{Message: "Email changed successfully"})
+}
+
+// UploadAvatar godoc
+// @Summary      Upload Avatar
+// @Description  Upload a jpeg, png, gif or webp image. It is cropped to a square and resized to standard thumbnails.
+// @Tags         Profile
+// @Accept       multipart/form-data
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Security     BearerAuth
+// @Param        avatar  formData  file  true  "Avatar image"
+// @Success      200  {object}  dto.ProfileResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      413  {object}  dto.Problem
+// @Failure      415  {object}  dto.Problem
+// @Failure      422  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /profile/avatar [put]
+func (pc *ProfileController) UploadAvatar(c *gin.Context) {
+	// multipart 包装额外留出 64KB 余量，文件本身大小在下面单独校验
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, pc.AvatarMaxBytes+64<<10)
+
+	fileHeader, err := c.FormFile("avatar")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			_ = c.Error(errAvatarTooLarge)
 			return
 		}
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		_ = c.Error(errAvatarRequired)
+		return
+	}
+	if fileHeader.Size > pc.AvatarMaxBytes {
+		_ = c.Error(errAvatarTooLarge)
 		return
 	}
 
-	c.JSON(http.StatusOK, domain.SuccessResponse{Message: "Password changed successfully"})
+	file, err := fileHeader.Open()
+	if err != nil {
+		_ = c.Error(errAvatarRequired)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(file, pc.AvatarMaxBytes))
+	if err != nil {
+		_ = c.Error(errAvatarRequired)
+		return
+	}
+
+	// 不信任客户端声明的 Content-Type，按文件内容嗅探
+	if !allowedAvatarTypes[http.DetectContentType(data)] {
+		_ = c.Error(domain.ErrUnsupportedMedia.WithMessage("avatar must be a jpeg, png, gif or webp image"))
+		return
+	}
+
+	userID := c.GetString("x-user-id")
+
+	profile, err := pc.ProfileUsecase.UploadAvatar(c.Request.Context(), userID, data)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	respond(c, http.StatusOK, newProfileResponse(profile))
+}
+
+// emailInUse 细化邮箱冲突时的提示信息
+func emailInUse(err error) error {
+	if errors.Is(err, domain.ErrUserAlreadyExists) {
+		return domain.AsError(err).WithMessage("email already in use")
+	}
+	return err
+}
+
+func newProfileResponse(profile *domain.Profile) dto.ProfileResponse {
+	return dto.ProfileResponse{
+		Name:       profile.Name,
+		Email:      profile.Email,
+		AvatarURL:  profile.AvatarURL,
+		Attributes: newProfileAttributes(profile.Attributes),
+	}
+}
+
+func newProfileAttributes(attributes domain.ProfileAttributes) dto.ProfileAttributes {
+	return dto.ProfileAttributes{
+		DisplayName: attributes.DisplayName,
+		Locale:      attributes.Locale,
+		Timezone:    attributes.Timezone,
+		Phone:       attributes.Phone,
+		Metadata:    attributes.Metadata,
+	}
 }
@@ -1,9 +1,12 @@
 package controller_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
+	"image"
+	"image/png"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -12,9 +15,18 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/horaoen/go-backend-clean-architecture/api/controller"
+	"github.com/horaoen/go-backend-clean-architecture/api/dto"
+	"github.com/horaoen/go-backend-clean-architecture/api/middleware"
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/tracing"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // MockProfileUsecase
@@ -35,6 +47,90 @@ func (m *MockProfileUsecase) ChangePassword(c context.Context, userID string, ol
 	return args.Error(0)
 }
 
+func (m *MockProfileUsecase) DeleteAccount(c context.Context, userID string, password string) error {
+	args := m.Called(c, userID, password)
+	return args.Error(0)
+}
+
+func (m *MockProfileUsecase) ExportData(c context.Context, userID string) (*domain.UserDataExport, error) {
+	args := m.Called(c, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.UserDataExport), args.Error(1)
+}
+
+func (m *MockProfileUsecase) UpdateProfile(c context.Context, userID string, update domain.ProfileUpdate) (*domain.Profile, error) {
+	args := m.Called(c, userID, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Profile), args.Error(1)
+}
+
+func (m *MockProfileUsecase) RequestEmailChange(c context.Context, userID string, password string, newEmail string) error {
+	args := m.Called(c, userID, password, newEmail)
+	return args.Error(0)
+}
+
+func (m *MockProfileUsecase) ConfirmEmailChange(c context.Context, token string) error {
+	args := m.Called(c, token)
+	return args.Error(0)
+}
+
+func (m *MockProfileUsecase) UploadAvatar(c context.Context, userID string, image []byte) (*domain.Profile, error) {
+	args := m.Called(c, userID, image)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Profile), args.Error(1)
+}
+
+func TestProfileController_Fetch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{
+			ProfileUsecase: mockUsecase,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", "1")
+		c.Request, _ = http.NewRequest(http.MethodGet, "/profile", nil)
+
+		mockUsecase.On("GetProfileByID", mock.Anything, "1").Return(&domain.Profile{Name: "Test User", Email: "test@example.com"}, nil)
+
+		serve(c, pc.Fetch)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.ProfileResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "test@example.com", response.Email)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("deleted_user", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{
+			ProfileUsecase: mockUsecase,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", "1")
+		c.Request, _ = http.NewRequest(http.MethodGet, "/profile", nil)
+
+		mockUsecase.On("GetProfileByID", mock.Anything, "1").Return(nil, domain.ErrUserNotFound)
+
+		serve(c, pc.Fetch)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
 func TestProfileController_ChangePassword(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -62,7 +158,7 @@ func TestProfileController_ChangePassword(t *testing.T) {
 
 		mockUsecase.On("ChangePassword", mock.Anything, userID, oldPassword, newPassword).Return(nil)
 
-		pc.ChangePassword(c)
+		serve(c, pc.ChangePassword)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		var response domain.SuccessResponse
@@ -90,11 +186,454 @@ func TestProfileController_ChangePassword(t *testing.T) {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		c.Request = req
 
-		mockUsecase.On("ChangePassword", mock.Anything, userID, "wrong", newPassword).Return(errors.New("invalid old password"))
+		mockUsecase.On("ChangePassword", mock.Anything, userID, "wrong", newPassword).Return(domain.ErrInvalidCredentials.WithMessage("invalid old password"))
+
+		serve(c, pc.ChangePassword)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		problem := decodeProblem(t, w)
+		assert.Equal(t, string(domain.CodeInvalidCredentials), problem.Code)
+		assert.Equal(t, "invalid old password", problem.Detail)
+		mockUsecase.AssertExpectations(t)
+	})
+}
+
+func TestProfileController_DeleteAccount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userID := "1"
+
+	t.Run("success", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{
+			ProfileUsecase: mockUsecase,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", userID)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/profile", strings.NewReader(`{"password":"password"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		mockUsecase.On("DeleteAccount", mock.Anything, userID, "password").Return(nil)
 
-		pc.ChangePassword(c)
+		serve(c, pc.DeleteAccount)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid_password", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{
+			ProfileUsecase: mockUsecase,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", userID)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/profile", strings.NewReader(`{"password":"wrong"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		mockUsecase.On("DeleteAccount", mock.Anything, userID, "wrong").Return(domain.ErrInvalidCredentials)
+
+		serve(c, pc.DeleteAccount)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 		mockUsecase.AssertExpectations(t)
 	})
+
+	t.Run("missing_password", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{
+			ProfileUsecase: mockUsecase,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", userID)
+
+		req, _ := http.NewRequest(http.MethodDelete, "/profile", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		serve(c, pc.DeleteAccount)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProfileController_Export(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUsecase := new(MockProfileUsecase)
+	pc := controller.ProfileController{
+		ProfileUsecase: mockUsecase,
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("x-user-id", "1")
+	c.Request, _ = http.NewRequest(http.MethodGet, "/profile/export", nil)
+
+	mockUsecase.On("ExportData", mock.Anything, "1").Return(&domain.UserDataExport{
+		ID:    1,
+		Name:  "Test User",
+		Email: "test@example.com",
+		Role:  domain.RoleUser,
+	}, nil)
+
+	serve(c, pc.Export)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "attachment")
+
+	var response dto.ProfileExportResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "test@example.com", response.Email)
+	assert.Equal(t, domain.RoleUser, response.Role)
+	assert.Nil(t, response.DisabledAt)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestProfileController_Update(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUsecase := new(MockProfileUsecase)
+	pc := controller.ProfileController{
+		ProfileUsecase: mockUsecase,
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Set("x-user-id", "1")
+
+	req, _ := http.NewRequest(http.MethodPatch, "/profile", strings.NewReader(`{"name":"New Name"}`))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+
+	mockUsecase.On("UpdateProfile", mock.Anything, "1", mock.MatchedBy(func(u domain.ProfileUpdate) bool {
+		return u.Name != nil && *u.Name == "New Name"
+	})).Return(&domain.Profile{Name: "New Name", Email: "test@example.com"}, nil)
+
+	serve(c, pc.Update)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response dto.ProfileResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "New Name", response.Name)
+	mockUsecase.AssertExpectations(t)
+}
+
+func TestProfileController_Update_Attributes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", "1")
+
+		req, _ := http.NewRequest(http.MethodPatch, "/profile", strings.NewReader(`{"locale":"zh-CN","metadata":{"theme":"dark","old":null}}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		mockUsecase.On("UpdateProfile", mock.Anything, "1", mock.MatchedBy(func(u domain.ProfileUpdate) bool {
+			_, hasOld := u.Attributes.Metadata["old"]
+			return u.Name == nil &&
+				u.Attributes.Locale != nil && *u.Attributes.Locale == "zh-CN" &&
+				u.Attributes.Timezone == nil &&
+				u.Attributes.Metadata["theme"] == "dark" && hasOld
+		})).Return(&domain.Profile{
+			Name:       "Test User",
+			Email:      "test@example.com",
+			Attributes: domain.ProfileAttributes{Locale: "zh-CN", Metadata: map[string]any{"theme": "dark"}},
+		}, nil)
+
+		serve(c, pc.Update)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.ProfileResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "zh-CN", response.Attributes.Locale)
+		assert.Equal(t, "dark", response.Attributes.Metadata["theme"])
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid_attributes", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", "1")
+
+		req, _ := http.NewRequest(http.MethodPatch, "/profile", strings.NewReader(`{"phone":"123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		c.Request = req
+
+		mockUsecase.On("UpdateProfile", mock.Anything, "1", mock.Anything).
+			Return(nil, domain.ErrInvalidProfileAttributes.WithFields(domain.FieldError{
+				Field: "phone", Rule: "e164", Message: "must be in E.164 format",
+			}))
+
+		serve(c, pc.Update)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		problem := decodeProblem(t, w)
+		assert.Equal(t, string(domain.CodeInvalidProfileAttributes), problem.Code)
+		if assert.Len(t, problem.Errors, 1) {
+			assert.Equal(t, "phone", problem.Errors[0].Field)
+			assert.Contains(t, problem.Errors[0].Message, "E.164")
+		}
+	})
+}
+
+func TestProfileController_RequestEmailChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRequest := func() (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", "1")
+
+		data := url.Values{}
+		data.Set("password", "password")
+		data.Set("newEmail", "new@example.com")
+
+		req, _ := http.NewRequest(http.MethodPost, "/profile/email", strings.NewReader(data.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		c.Request = req
+		return w, c
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase}
+		w, c := newRequest()
+
+		mockUsecase.On("RequestEmailChange", mock.Anything, "1", "password", "new@example.com").Return(nil)
+
+		serve(c, pc.RequestEmailChange)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("email_taken", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase}
+		w, c := newRequest()
+
+		mockUsecase.On("RequestEmailChange", mock.Anything, "1", "password", "new@example.com").Return(domain.ErrUserAlreadyExists)
+
+		serve(c, pc.RequestEmailChange)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestProfileController_ConfirmEmailChange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("success", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/profile/email/confirm", strings.NewReader("token=change_token"))
+		c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		mockUsecase.On("ConfirmEmailChange", mock.Anything, "change_token").Return(nil)
+
+		serve(c, pc.ConfirmEmailChange)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("invalid_token", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodPost, "/profile/email/confirm", strings.NewReader(`{"token":"bad"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		mockUsecase.On("ConfirmEmailChange", mock.Anything, "bad").Return(domain.ErrInvalidToken)
+
+		serve(c, pc.ConfirmEmailChange)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestProfileController_ConfirmEmailChangePage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("renders_form_without_confirming", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, `/profile/email/confirm?token=a"><script>`, nil)
+
+		serve(c, pc.ConfirmEmailChangePage)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.Contains(t, w.Body.String(), `<form method="post">`)
+		assert.Contains(t, w.Body.String(), `value="a&#34;&gt;&lt;script&gt;"`, "token is escaped")
+		mockUsecase.AssertNotCalled(t, "ConfirmEmailChange", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing_token", func(t *testing.T) {
+		pc := controller.ProfileController{ProfileUsecase: new(MockProfileUsecase)}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest(http.MethodGet, "/profile/email/confirm", nil)
+
+		serve(c, pc.ConfirmEmailChangePage)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func newAvatarRequest(t *testing.T, content []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("avatar", "avatar.png")
+	assert.NoError(t, err)
+	_, _ = part.Write(content)
+	_ = writer.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, "/profile/avatar", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestProfileController_UploadAvatar(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var pngData bytes.Buffer
+	_ = png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 10, 10)))
+
+	t.Run("success", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase, AvatarMaxBytes: 1 << 20}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", "1")
+		c.Request = newAvatarRequest(t, pngData.Bytes())
+
+		mockUsecase.On("UploadAvatar", mock.Anything, "1", pngData.Bytes()).Return(&domain.Profile{
+			Name:      "Test User",
+			Email:     "test@example.com",
+			AvatarURL: "http://localhost:8080/uploads/avatars/1/x/256.png",
+		}, nil)
+
+		serve(c, pc.UploadAvatar)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response dto.ProfileResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://localhost:8080/uploads/avatars/1/x/256.png", response.AvatarURL)
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("unsupported_type", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase, AvatarMaxBytes: 1 << 20}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", "1")
+		c.Request = newAvatarRequest(t, []byte("<html>not an image</html>"))
+
+		serve(c, pc.UploadAvatar)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+		mockUsecase.AssertNotCalled(t, "UploadAvatar", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("too_large", func(t *testing.T) {
+		mockUsecase := new(MockProfileUsecase)
+		pc := controller.ProfileController{ProfileUsecase: mockUsecase, AvatarMaxBytes: 16}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Set("x-user-id", "1")
+		c.Request = newAvatarRequest(t, pngData.Bytes())
+
+		serve(c, pc.UploadAvatar)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		mockUsecase.AssertNotCalled(t, "UploadAvatar", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProfileController_UsecaseSpanIsChildOfRequestSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+	})
+
+	mockUsecase := new(MockProfileUsecase)
+	mockUsecase.On("GetProfileByID", mock.Anything, "1").Return(&domain.Profile{Name: "Test User"}, nil)
+	pc := controller.ProfileController{ProfileUsecase: tracing.TraceProfile(mockUsecase)}
+
+	router := gin.New()
+	router.Use(middleware.TracingMiddleware(), func(c *gin.Context) { c.Set("x-user-id", "1") })
+	router.GET("/profile", pc.Fetch)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/profile", nil))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	usecaseSpan, serverSpan := spans[0], spans[1]
+	assert.Equal(t, "ProfileUsecase.GetProfileByID", usecaseSpan.Name)
+	assert.Equal(t, serverSpan.SpanContext.TraceID(), usecaseSpan.SpanContext.TraceID())
+	assert.Equal(t, serverSpan.SpanContext.SpanID(), usecaseSpan.Parent.SpanID())
+}
+
+func TestProfileController_UsecaseLogsCarryRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = previous })
+
+	mockUsecase := new(MockProfileUsecase)
+	mockUsecase.On("GetProfileByID", mock.Anything, "1").Run(func(args mock.Arguments) {
+		log.Ctx(args.Get(0).(context.Context)).Info().Msg("usecase called")
+	}).Return(&domain.Profile{Name: "Test User"}, nil)
+	pc := controller.ProfileController{ProfileUsecase: mockUsecase}
+
+	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware(), func(c *gin.Context) { c.Set("x-user-id", "1") })
+	router.GET("/profile", pc.Fetch)
+	req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+	req.Header.Set("X-Request-ID", "req-profile-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, buf.String(), `"request_id":"req-profile-1"`)
+	assert.Contains(t, buf.String(), `"message":"usecase called"`)
 }
@@ -13,28 +13,38 @@ type RefreshTokenController struct {
 	RefreshTokenUsecase domain.RefreshTokenUsecase
 }
 
+// RefreshToken godoc
+// @Summary      Refresh Token
+// @Description  Refresh access token using refresh token
+// @Tags         Auth
+// @Accept       x-www-form-urlencoded,json
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Param        request  formData  dto.RefreshTokenRequest  true  "Refresh token"
+// @Success      200  {object}  dto.RefreshTokenResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      403  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /refresh [post]
 func (rtc *RefreshTokenController) RefreshToken(c *gin.Context) {
 	var request dto.RefreshTokenRequest
 
 	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		_ = c.Error(bindError(err))
 		return
 	}
 
 	tokens, err := rtc.RefreshTokenUsecase.Refresh(c.Request.Context(), request.RefreshToken)
 	if err != nil {
-		switch {
-		case errors.Is(err, domain.ErrInvalidToken):
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "invalid or expired token"})
-		case errors.Is(err, domain.ErrUserNotFound):
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "user not found"})
-		default:
-			c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "internal server error"})
+		// 用户已被删除时 refresh token 同样视为失效
+		if errors.Is(err, domain.ErrUserNotFound) {
+			err = domain.AsError(err).WithStatus(http.StatusUnauthorized)
 		}
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.RefreshTokenResponse{
+	respond(c, http.StatusOK, dto.RefreshTokenResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 	})
@@ -52,7 +52,7 @@ func TestRefreshTokenController_RefreshToken(t *testing.T) {
 
 		mockUsecase.On("Refresh", mock.Anything, "valid_refresh_token").Return(expectedTokens, nil)
 
-		rtc.RefreshToken(c)
+		serve(c, rtc.RefreshToken)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
@@ -83,14 +83,13 @@ func TestRefreshTokenController_RefreshToken(t *testing.T) {
 
 		mockUsecase.On("Refresh", mock.Anything, "invalid_token").Return(domain.TokenPair{}, domain.ErrInvalidToken)
 
-		rtc.RefreshToken(c)
+		serve(c, rtc.RefreshToken)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "invalid or expired token", response.Message)
+		problem := decodeProblem(t, w)
+		assert.Equal(t, string(domain.CodeInvalidToken), problem.Code)
+		assert.Equal(t, "invalid or expired token", problem.Detail)
 
 		mockUsecase.AssertExpectations(t)
 	})
@@ -113,14 +112,13 @@ func TestRefreshTokenController_RefreshToken(t *testing.T) {
 
 		mockUsecase.On("Refresh", mock.Anything, "valid_token_for_missing_user").Return(domain.TokenPair{}, domain.ErrUserNotFound)
 
-		rtc.RefreshToken(c)
+		serve(c, rtc.RefreshToken)
 
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "user not found", response.Message)
+		problem := decodeProblem(t, w)
+		assert.Equal(t, string(domain.CodeUserNotFound), problem.Code)
+		assert.Equal(t, http.StatusUnauthorized, problem.Status)
 
 		mockUsecase.AssertExpectations(t)
 	})
@@ -0,0 +1,43 @@
+package controller
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// respond 按 ContentNegotiationMiddleware 协商出的格式写出响应，未经协商时为 JSON。
+// MessagePack 与 Protobuf 的字段名与 JSON 一致，Protobuf 响应体为 google.protobuf.Struct
+func respond(c *gin.Context, status int, obj any) {
+	switch format := c.GetString("x-response-format"); format {
+	case binding.MIMEMSGPACK, binding.MIMEMSGPACK2:
+		c.Header("Content-Type", format)
+		c.Render(status, render.MsgPack{Data: obj})
+	case binding.MIMEPROTOBUF:
+		message, err := toStruct(obj)
+		if err != nil {
+			_ = c.Error(domain.ErrInternalServer.Wrap(err))
+			return
+		}
+		c.ProtoBuf(status, message)
+	default:
+		c.JSON(status, obj)
+	}
+}
+
+// toStruct 经由 JSON 编码转换，使 Protobuf 响应沿用 json 标签和 omitempty 规则
+func toStruct(obj any) (*structpb.Struct, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
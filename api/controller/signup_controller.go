@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,26 +12,39 @@ type SignupController struct {
 	SignupUsecase domain.SignupUsecase
 }
 
+// Signup godoc
+// @Summary      Signup
+// @Description  Register a new user. When signup concealment is enabled the request is always accepted with 202 and the result is sent by email.
+// @Tags         Auth
+// @Accept       x-www-form-urlencoded,json
+// @Produce      json,application/x-msgpack,application/x-protobuf
+// @Param        request  formData  dto.SignupRequest  true  "Signup data"
+// @Success      200  {object}  dto.SignupResponse
+// @Success      202  {object}  domain.SuccessResponse
+// @Failure      400  {object}  dto.Problem
+// @Failure      409  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /signup [post]
 func (sc *SignupController) Signup(c *gin.Context) {
 	var request dto.SignupRequest
 
 	if err := c.ShouldBind(&request); err != nil {
-		c.JSON(http.StatusBadRequest, domain.ErrorResponse{Message: err.Error()})
+		_ = c.Error(bindError(err))
 		return
 	}
 
 	tokens, err := sc.SignupUsecase.Signup(c.Request.Context(), request.Name, request.Email, request.Password)
 	if err != nil {
-		switch {
-		case errors.Is(err, domain.ErrUserAlreadyExists):
-			c.JSON(http.StatusConflict, domain.ErrorResponse{Message: "user already exists with the given email"})
-		default:
-			c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: "internal server error"})
-		}
+		_ = c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.SignupResponse{
+	if tokens == (domain.TokenPair{}) {
+		respond(c, http.StatusAccepted, domain.SuccessResponse{Message: "signup received, please check your email"})
+		return
+	}
+
+	respond(c, http.StatusOK, dto.SignupResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 	})
@@ -54,7 +54,7 @@ func TestSignupController_Signup(t *testing.T) {
 
 		mockUsecase.On("Signup", mock.Anything, "Test User", "test@example.com", "password").Return(expectedTokens, nil)
 
-		sc.Signup(c)
+		serve(c, sc.Signup)
 
 		assert.Equal(t, http.StatusOK, w.Code)
 
@@ -87,14 +87,40 @@ func TestSignupController_Signup(t *testing.T) {
 
 		mockUsecase.On("Signup", mock.Anything, "Test User", "existing@example.com", "password").Return(domain.TokenPair{}, domain.ErrUserAlreadyExists)
 
-		sc.Signup(c)
+		serve(c, sc.Signup)
 
 		assert.Equal(t, http.StatusConflict, w.Code)
 
-		var response domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "user already exists with the given email", response.Message)
+		problem := decodeProblem(t, w)
+		assert.Equal(t, string(domain.CodeUserAlreadyExists), problem.Code)
+
+		mockUsecase.AssertExpectations(t)
+	})
+
+	t.Run("accepted", func(t *testing.T) {
+		mockUsecase := new(MockSignupUsecase)
+		sc := controller.SignupController{
+			SignupUsecase: mockUsecase,
+		}
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		data := url.Values{}
+		data.Set("name", "Test User")
+		data.Set("email", "test@example.com")
+		data.Set("password", "password")
+
+		req, _ := http.NewRequest(http.MethodPost, "/signup", strings.NewReader(data.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		c.Request = req
+
+		mockUsecase.On("Signup", mock.Anything, "Test User", "test@example.com", "password").Return(domain.TokenPair{}, nil)
+
+		serve(c, sc.Signup)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.NotContains(t, w.Body.String(), "accessToken")
 
 		mockUsecase.AssertExpectations(t)
 	})
@@ -116,7 +142,7 @@ func TestSignupController_Signup(t *testing.T) {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		c.Request = req
 
-		sc.Signup(c)
+		serve(c, sc.Signup)
 
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
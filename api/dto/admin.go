@@ -0,0 +1,9 @@
+package dto
+
+type LogLevelRequest struct {
+	Level string `form:"level" json:"level" binding:"required" example:"debug"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level" example:"info"`
+}
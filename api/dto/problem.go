@@ -0,0 +1,22 @@
+package dto
+
+// Problem 为 RFC 7807 错误响应体，Content-Type 为 application/problem+json。
+// code 为稳定的错误码，客户端应据此判断错误类型；detail 仅供展示，内容可能调整。
+type Problem struct {
+	Type      string         `json:"type" example:"urn:problem-type:validation_failed"`
+	Title     string         `json:"title" example:"Bad Request"`
+	Status    int            `json:"status" example:"400"`
+	Detail    string         `json:"detail,omitempty" example:"request validation failed"`
+	Instance  string         `json:"instance,omitempty" example:"/api/v1/signup"`
+	Code      string         `json:"code" example:"validation_failed" enums:"invalid_request,validation_failed,unauthorized,invalid_credentials,invalid_token,forbidden,user_disabled,not_found,user_not_found,user_already_exists,payload_too_large,unsupported_media_type,not_acceptable,invalid_image,invalid_profile_attributes,invalid_role,invalid_log_level,rate_limited,timeout,internal_error"`
+	RequestID string         `json:"requestId,omitempty" example:"01HZX3K5J8Q2W7N4R6T9V0B1C2"`
+	Errors    []ProblemField `json:"errors,omitempty"`
+}
+
+// ProblemField 为单个字段的校验错误，rule 为失败的校验规则
+type ProblemField struct {
+	Field   string `json:"field" example:"email"`
+	Rule    string `json:"rule" example:"email"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message" example:"must be a valid email address"`
+}
@@ -1,11 +1,60 @@
 package dto
 
+import "time"
+
 type ChangePasswordRequest struct {
-	OldPassword string `form:"oldPassword" binding:"required"`
-	NewPassword string `form:"newPassword" binding:"required,min=6"`
+	OldPassword string `form:"oldPassword" json:"oldPassword" binding:"required"`
+	NewPassword string `form:"newPassword" json:"newPassword" binding:"required,min=6"`
+}
+
+type ProfileAttributes struct {
+	DisplayName string         `json:"displayName,omitempty"`
+	Locale      string         `json:"locale,omitempty" example:"zh-CN"`
+	Timezone    string         `json:"timezone,omitempty" example:"Asia/Shanghai"`
+	Phone       string         `json:"phone,omitempty" example:"+8613800138000"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
 }
 
 type ProfileResponse struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name       string            `json:"name"`
+	Email      string            `json:"email"`
+	AvatarURL  string            `json:"avatarUrl,omitempty"`
+	Attributes ProfileAttributes `json:"attributes"`
+}
+
+// UpdateProfileRequest 中未提供的字段保持不变，扩展属性传空字符串表示清除；
+// metadata 仅支持 JSON 请求体，按键合并，值为 null 的键被删除
+type UpdateProfileRequest struct {
+	Name        *string        `form:"name" json:"name" binding:"omitempty,min=1,max=255"`
+	DisplayName *string        `form:"displayName" json:"displayName"`
+	Locale      *string        `form:"locale" json:"locale" example:"zh-CN"`
+	Timezone    *string        `form:"timezone" json:"timezone" example:"Asia/Shanghai"`
+	Phone       *string        `form:"phone" json:"phone" example:"+8613800138000"`
+	Metadata    map[string]any `form:"-" json:"metadata"`
+}
+
+type ChangeEmailRequest struct {
+	Password string `form:"password" json:"password" binding:"required"`
+	NewEmail string `form:"newEmail" json:"newEmail" binding:"required,email"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `form:"token" json:"token" binding:"required"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `form:"password" json:"password" binding:"required"`
+}
+
+type ProfileExportResponse struct {
+	ID         uint              `json:"id"`
+	Name       string            `json:"name"`
+	Email      string            `json:"email"`
+	AvatarURL  string            `json:"avatarUrl,omitempty"`
+	Attributes ProfileAttributes `json:"attributes"`
+	Role       string            `json:"role"`
+	DisabledAt *time.Time        `json:"disabledAt,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+	ExportedAt time.Time         `json:"exportedAt"`
 }
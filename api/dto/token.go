@@ -1,7 +1,7 @@
 package dto
 
 type RefreshTokenRequest struct {
-	RefreshToken string `form:"refreshToken" binding:"required"`
+	RefreshToken string `form:"refreshToken" json:"refreshToken" binding:"required"`
 }
 
 type RefreshTokenResponse struct {
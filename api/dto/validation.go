@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ConfigureBinding 调整 gin 的全局绑定行为：JSON 请求体拒绝未知字段，
+// 校验错误使用请求中的字段名（form 或 json 标签）而不是 Go 字段名
+func ConfigureBinding() {
+	binding.EnableDecoderDisallowUnknownFields = true
+
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"form", "json"} {
+			name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+			if name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const redactedValue = "[REDACTED]"
+
+// DefaultRedactFields 为默认脱敏的查询参数和请求头名称
+var DefaultRedactFields = []string{"authorization", "cookie", "password", "token", "access_token", "refresh_token"}
+
+type AccessLogConfig struct {
+	// RedactFields 为需要脱敏的查询参数和请求头名称，不区分大小写，为空时使用 DefaultRedactFields
+	RedactFields []string
+	// Headers 为额外记录的请求头
+	Headers []string
+}
+
+// AccessLogMiddleware 请求结束后使用请求 ctx 中的 logger 输出一条访问日志，
+// 5xx 记为 error，4xx 记为 warn，其余为 info
+func AccessLogMiddleware(config AccessLogConfig) gin.HandlerFunc {
+	fields := config.RedactFields
+	if len(fields) == 0 {
+		fields = DefaultRedactFields
+	}
+	redact := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		redact[strings.ToLower(strings.TrimSpace(field))] = true
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		logger := log.Ctx(c.Request.Context())
+		var event *zerolog.Event
+		switch {
+		case status >= http.StatusInternalServerError:
+			event = logger.Error()
+		case status >= http.StatusBadRequest:
+			event = logger.Warn()
+		default:
+			event = logger.Info()
+		}
+
+		event = event.
+			Str("method", c.Request.Method).
+			Str("path", c.Request.URL.Path).
+			Str("route", c.FullPath()).
+			Int("status", status).
+			Int("bytes", max(c.Writer.Size(), 0)).
+			Dur("latency", time.Since(start)).
+			Str("client_ip", c.ClientIP()).
+			Str("user_agent", c.Request.UserAgent())
+		if query := redactQuery(c.Request.URL.RawQuery, redact); query != "" {
+			event = event.Str("query", query)
+		}
+		if userID := c.GetString("x-user-id"); userID != "" {
+			event = event.Str("user_id", userID)
+		}
+		for _, header := range config.Headers {
+			if value := c.GetHeader(header); value != "" {
+				if redact[strings.ToLower(header)] {
+					value = redactedValue
+				}
+				event = event.Str("header."+strings.ToLower(header), value)
+			}
+		}
+		if len(c.Errors) > 0 {
+			event = event.Str("errors", c.Errors.String())
+		}
+		event.Msg("request")
+	}
+}
+
+func redactQuery(rawQuery string, redact map[string]bool) string {
+	if rawQuery == "" {
+		return ""
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return redactedValue
+	}
+	for key := range values {
+		if redact[strings.ToLower(key)] {
+			values[key] = []string{redactedValue}
+		}
+	}
+	return values.Encode()
+}
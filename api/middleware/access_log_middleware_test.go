@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/internal/requestid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = previous })
+	return &buf
+}
+
+func setupAccessLogRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), AccessLogMiddleware(AccessLogConfig{
+		RedactFields: DefaultRedactFields,
+		Headers:      []string{"Referer", "Authorization"},
+	}))
+	r.GET("/items/:id", handler)
+	return r
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	buf := captureLog(t)
+	var ctxRequestID string
+	r := setupAccessLogRouter(func(c *gin.Context) {
+		ctxRequestID = requestid.FromContext(c.Request.Context())
+		c.Set("x-user-id", "42")
+		log.Ctx(c.Request.Context()).Info().Msg("inside handler")
+		c.String(http.StatusOK, "hello")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items/7?token=abc&page=2", nil)
+	req.Header.Set("Referer", "https://example.com")
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	requestID := w.Header().Get(requestid.Header)
+	assert.True(t, requestid.Valid(requestID))
+	assert.Equal(t, requestID, ctxRequestID)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var handlerLine, accessLine map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &handlerLine))
+	require.NoError(t, json.Unmarshal(lines[1], &accessLine))
+
+	assert.Equal(t, requestID, handlerLine["request_id"])
+	assert.Equal(t, requestID, accessLine["request_id"])
+	assert.Equal(t, "/items/:id", accessLine["route"])
+	assert.Equal(t, float64(200), accessLine["status"])
+	assert.Equal(t, float64(5), accessLine["bytes"])
+	assert.Equal(t, "42", accessLine["user_id"])
+	assert.Equal(t, "page=2&token=%5BREDACTED%5D", accessLine["query"])
+	assert.Equal(t, "https://example.com", accessLine["header.referer"])
+	assert.Equal(t, "[REDACTED]", accessLine["header.authorization"])
+	assert.Contains(t, accessLine, "latency")
+}
+
+func TestRequestIDMiddleware_PropagatesValidID(t *testing.T) {
+	captureLog(t)
+	r := setupAccessLogRouter(func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	req := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set(requestid.Header, "upstream-id-1")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "upstream-id-1", w.Header().Get(requestid.Header))
+
+	req = httptest.NewRequest(http.MethodGet, "/items/1", nil)
+	req.Header.Set(requestid.Header, "bad id\nwith newline")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.NotEqual(t, "bad id\nwith newline", w.Header().Get(requestid.Header))
+	assert.True(t, requestid.Valid(w.Header().Get(requestid.Header)))
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+// BodyLimitMiddleware 限制请求体不超过 maxBytes：声明的 Content-Length 超限时直接返回 413，
+// 未声明长度时在读取超限后由请求绑定返回 payload_too_large
+func BodyLimitMiddleware(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			_ = c.Error(domain.ErrPayloadTooLarge)
+			c.Abort()
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+var (
+	// requestFormats 为业务接口接受的请求体格式
+	requestFormats = []string{binding.MIMEJSON, binding.MIMEPOSTForm, binding.MIMEMultipartPOSTForm}
+	// responseFormats 为可协商的响应格式，Accept 缺省时使用第一个
+	responseFormats = []string{binding.MIMEJSON, binding.MIMEMSGPACK, binding.MIMEMSGPACK2, binding.MIMEPROTOBUF}
+)
+
+// ContentNegotiationMiddleware 拒绝不支持的请求体格式（415），并按 Accept 选择响应格式，
+// 结果写入 "x-response-format" 供 controller 使用；没有可接受的格式时返回 406
+func ContentNegotiationMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if hasBody(c.Request) && !slices.Contains(requestFormats, c.ContentType()) {
+			_ = c.Error(domain.ErrUnsupportedMedia)
+			c.Abort()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept")
+		format := c.NegotiateFormat(responseFormats...)
+		if format == "" {
+			_ = c.Error(domain.ErrNotAcceptable)
+			c.Abort()
+			return
+		}
+		c.Set("x-response-format", format)
+
+		c.Next()
+	}
+}
+
+func hasBody(r *http.Request) bool {
+	return r.ContentLength > 0 || len(r.TransferEncoding) > 0
+}
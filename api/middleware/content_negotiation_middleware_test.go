@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentNegotiationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(ErrorMiddleware(), ContentNegotiationMiddleware())
+	r.POST("/echo", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("x-response-format"))
+	})
+
+	send := func(contentType, accept, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		accept      string
+		body        string
+		status      int
+		format      string
+	}{
+		{"default_json", "application/json", "", `{}`, http.StatusOK, "application/json"},
+		{"charset_param", "application/json; charset=utf-8", "*/*", `{}`, http.StatusOK, "application/json"},
+		{"form", "application/x-www-form-urlencoded", "application/json", "a=1", http.StatusOK, "application/json"},
+		{"no_body", "", "application/x-msgpack", "", http.StatusOK, "application/x-msgpack"},
+		{"protobuf", "application/json", "application/x-protobuf", `{}`, http.StatusOK, "application/x-protobuf"},
+		{"unsupported_body", "text/plain", "", "hello", http.StatusUnsupportedMediaType, ""},
+		{"not_acceptable", "application/json", "text/html", `{}`, http.StatusNotAcceptable, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := send(tt.contentType, tt.accept, tt.body)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.format, w.Body.String())
+			}
+			if tt.status == http.StatusNotAcceptable {
+				assert.Contains(t, w.Body.String(), string(domain.CodeNotAcceptable))
+			}
+		})
+	}
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(ErrorMiddleware(), BodyLimitMiddleware(8))
+	r.POST("/read", func(c *gin.Context) {
+		if _, err := io.ReadAll(c.Request.Body); err != nil {
+			_ = c.Error(domain.ErrPayloadTooLarge.Wrap(err))
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	send := func(body string, chunked bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/read", strings.NewReader(body))
+		if chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, send("12345678", false))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("123456789", false))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, send("123456789", true))
+}
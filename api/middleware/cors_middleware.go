@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+	"github.com/horaoen/go-backend-clean-architecture/internal/requestid"
+)
+
+const (
+	corsAllowMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowHeaders = "Authorization, Content-Type, Accept, Accept-Language, " + requestid.Header
+	corsMaxAge       = "600"
+)
+
+// CORSMiddleware 仅对 origins 中的来源返回 CORS 头，"*" 表示允许任意来源。
+// 只有显式列出的来源才允许携带凭据，经 "*" 放行的请求返回字面量 "*" 且不带 Allow-Credentials，
+// 避免任意站点以用户身份发起跨域请求。
+// origins 每个请求读取一次，配置热更新后立即生效；预检请求直接返回 204
+func CORSMiddleware(origins *dynconf.Value[[]string]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		allowed := origins.Load()
+		listed := slices.Contains(allowed, origin)
+		if !listed && !slices.Contains(allowed, "*") {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		if listed {
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Set("Access-Control-Allow-Credentials", "true")
+		} else {
+			h.Set("Access-Control-Allow-Origin", "*")
+		}
+		h.Set("Access-Control-Expose-Headers", requestid.Header)
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+			h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			h.Set("Access-Control-Max-Age", corsMaxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	origins := dynconf.NewValue([]string{"https://app.example.com"})
+	r := gin.New()
+	r.Use(CORSMiddleware(origins))
+	r.GET("/profile", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/profile", nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodGet, "https://app.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+
+	w = serve(http.MethodOptions, "https://app.example.com")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+
+	w = serve(http.MethodGet, "https://evil.example.com")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+	origins.Store([]string{"https://evil.example.com"})
+	w = serve(http.MethodGet, "https://evil.example.com")
+	assert.Equal(t, "https://evil.example.com", w.Header().Get("Access-Control-Allow-Origin"), "reloaded origins apply immediately")
+
+	origins.Store([]string{"https://app.example.com", "*"})
+	w = serve(http.MethodGet, "https://evil.example.com")
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"), "wildcard is not reflected")
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"), "wildcard never allows credentials")
+	w = serve(http.MethodGet, "https://app.example.com")
+	assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"), "listed origins keep credentials")
+}
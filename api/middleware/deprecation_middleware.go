@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationConfig 描述已弃用路由的响应头：Since 输出为 Deprecation（RFC 9745），
+// Sunset 非零时输出 Sunset（RFC 8594），SuccessorPrefix 非空时输出指向新版本同名路由的 Link
+type DeprecationConfig struct {
+	Since           time.Time
+	Sunset          time.Time
+	SuccessorPrefix string
+}
+
+// DeprecationMiddleware 为已弃用路由添加弃用响应头，不改变请求处理
+func DeprecationMiddleware(config DeprecationConfig) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(config.Since.Unix(), 10)
+	var sunset string
+	if !config.Sunset.IsZero() {
+		sunset = config.Sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Deprecation", deprecation)
+		if sunset != "" {
+			header.Set("Sunset", sunset)
+		}
+		if config.SuccessorPrefix != "" {
+			header.Add("Link", "<"+config.SuccessorPrefix+c.Request.URL.Path+`>; rel="successor-version"`)
+		}
+
+		c.Next()
+	}
+}
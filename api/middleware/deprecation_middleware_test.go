@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	since := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	serve := func(config DeprecationConfig) *httptest.ResponseRecorder {
+		r := gin.New()
+		r.Use(DeprecationMiddleware(config))
+		r.POST("/login", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+		return w
+	}
+
+	t.Run("with_sunset", func(t *testing.T) {
+		w := serve(DeprecationConfig{
+			Since:           since,
+			Sunset:          time.Date(2027, time.March, 31, 0, 0, 0, 0, time.UTC),
+			SuccessorPrefix: "/api/v1",
+		})
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Wed, 31 Mar 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `</api/v1/login>; rel="successor-version"`, w.Header().Get("Link"))
+	})
+
+	t.Run("without_sunset", func(t *testing.T) {
+		w := serve(DeprecationConfig{Since: since})
+
+		assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+		assert.Empty(t, w.Header().Get("Sunset"))
+		assert.Empty(t, w.Header().Get("Link"))
+	})
+}
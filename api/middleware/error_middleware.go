@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/dto"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/i18n"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/text/language"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:problem-type:"
+)
+
+// ErrorMiddleware 统一输出错误响应：handler 和其他中间件通过 c.Error 记录错误后直接返回，
+// 由这里把最后一个错误转换为 RFC 7807 problem+json。需放在其他中间件之前
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		RenderErrors(c)
+	}
+}
+
+// NotFound 用作 NoRoute 处理函数，使未匹配的路由同样返回 problem+json
+func NotFound(c *gin.Context) {
+	_ = c.Error(domain.ErrNotFound)
+}
+
+// RenderErrors 将 c.Errors 中最后一个错误写为 problem+json 响应，detail 和字段消息按请求语言翻译；
+// 已写出响应或没有错误时不做任何事
+func RenderErrors(c *gin.Context) {
+	if len(c.Errors) == 0 || c.Writer.Written() {
+		return
+	}
+	err := c.Errors.Last().Err
+	e := domain.AsError(err)
+	status := e.HTTPStatus()
+	tag := i18n.FromContext(c.Request.Context())
+
+	// 原因只写日志，不返回给客户端
+	if status >= http.StatusInternalServerError && e.Err != nil {
+		log.Ctx(c.Request.Context()).Error().Err(err).Str("code", string(e.Code)).Msg("request failed")
+	}
+
+	problem := dto.Problem{
+		Type:      problemTypePrefix + string(e.Code),
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    i18n.Translate(tag, e.Message),
+		Instance:  c.Request.URL.Path,
+		Code:      string(e.Code),
+		RequestID: c.GetString("x-request-id"),
+	}
+	for _, f := range e.Fields {
+		problem.Errors = append(problem.Errors, dto.ProblemField{
+			Field:   f.Field,
+			Rule:    f.Rule,
+			Param:   f.Param,
+			Message: fieldMessage(tag, f),
+		})
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.JSON(status, problem)
+}
+
+// fieldMessage 优先按校验规则生成消息，使同一规则在各处的措辞一致；未知规则翻译原消息
+func fieldMessage(tag language.Tag, f domain.FieldError) string {
+	if msg, ok := i18n.FieldMessage(tag, f.Rule, f.Param); ok {
+		return msg
+	}
+	return i18n.Translate(tag, f.Message)
+}
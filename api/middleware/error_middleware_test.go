@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/dto"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), ErrorMiddleware())
+	r.GET("/validation", func(c *gin.Context) {
+		_ = c.Error(domain.ErrValidation.WithFields(domain.FieldError{
+			Field: "email", Rule: "required", Message: "is required",
+		}))
+	})
+	r.GET("/internal", func(c *gin.Context) {
+		_ = c.Error(errors.New("dial tcp 10.0.0.1:5432: connection refused"))
+	})
+	r.GET("/written", func(c *gin.Context) {
+		c.String(http.StatusAccepted, "accepted")
+		_ = c.Error(domain.ErrInvalidRequest)
+	})
+	r.NoRoute(NotFound)
+
+	serve := func(path string) (*httptest.ResponseRecorder, dto.Problem) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(requestid.Header, "req-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var problem dto.Problem
+		if w.Header().Get("Content-Type") == problemContentType {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		}
+		return w, problem
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		w, problem := serve("/validation")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, "urn:problem-type:validation_failed", problem.Type)
+		assert.Equal(t, "Bad Request", problem.Title)
+		assert.Equal(t, string(domain.CodeValidationFailed), problem.Code)
+		assert.Equal(t, "/validation", problem.Instance)
+		assert.Equal(t, "req-1", problem.RequestID)
+		assert.Equal(t, []dto.ProblemField{{Field: "email", Rule: "required", Message: "is required"}}, problem.Errors)
+	})
+
+	t.Run("internal_error_hides_cause", func(t *testing.T) {
+		w, problem := serve("/internal")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, string(domain.CodeInternal), problem.Code)
+		assert.NotContains(t, w.Body.String(), "connection refused")
+	})
+
+	t.Run("already_written", func(t *testing.T) {
+		w, _ := serve("/written")
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Equal(t, "accepted", w.Body.String())
+	})
+
+	t.Run("no_route", func(t *testing.T) {
+		w, problem := serve("/missing")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, string(domain.CodeNotFound), problem.Code)
+	})
+}
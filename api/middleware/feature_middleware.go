@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+)
+
+// RequireFeature 在功能开关 name 关闭时返回 404，使未发布的接口对外不可见
+func RequireFeature(flags *dynconf.Value[domain.FeatureFlags], name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !flags.Load().Enabled(name) {
+			_ = c.Error(domain.ErrNotFound)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,7 +2,6 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -10,26 +9,48 @@ import (
 	"github.com/horaoen/go-backend-clean-architecture/internal/tokenutil"
 )
 
-func JwtAuthMiddleware(secret string) gin.HandlerFunc {
+// JwtAuthMiddleware 校验 access token，依次尝试 secret 的候选密钥以支持密钥轮换；
+// token 有效时再通过 accounts 确认账号未被停用或删除，并以账号当前的角色代替 token 中的角色
+func JwtAuthMiddleware(secret domain.Secret, accounts domain.AccountStatusUsecase) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "missing or invalid authorization header"})
+			_ = c.Error(domain.ErrUnauthorized)
 			c.Abort()
 			return
 		}
 
 		authToken := strings.TrimPrefix(authHeader, "Bearer ")
 
-		userID, err := tokenutil.ExtractIDFromToken(authToken, secret)
+		claims, err := parseAccessToken(authToken, secret)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "invalid or expired token"})
+			_ = c.Error(domain.ErrInvalidToken.Wrap(err))
 			c.Abort()
 			return
 		}
 
-		c.Set("x-user-id", userID)
+		role, err := accounts.CheckActive(c.Request.Context(), claims.ID)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Set("x-user-id", claims.ID)
+		// token 签发后角色可能已被调整，x-user-role 取数据库中的当前角色，降级立即生效
+		c.Set("x-user-role", role)
 		c.Next()
 	}
 }
+
+func parseAccessToken(authToken string, secret domain.Secret) (*domain.JwtCustomClaims, error) {
+	var err error
+	for _, key := range secret.Candidates() {
+		var claims *domain.JwtCustomClaims
+		if claims, err = tokenutil.ParseAccessToken(authToken, key); err == nil {
+			return claims, nil
+		}
+	}
+	return nil, err
+}
@@ -2,6 +2,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v4"
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/secrets"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,10 +23,25 @@ func createTestToken(claims *domain.JwtCustomClaims, secret string) string {
 	return tokenString
 }
 
+// accountStatusFunc 将函数适配为 domain.AccountStatusUsecase
+type accountStatusFunc func(userID string) (string, error)
+
+func (f accountStatusFunc) CheckActive(_ context.Context, userID string) (string, error) {
+	return f(userID)
+}
+
 func setupRouter(secret string) *gin.Engine {
+	return setupRouterWithSecret(secrets.Static(secret))
+}
+
+func setupRouterWithSecret(secret domain.Secret) *gin.Engine {
+	return setupRouterWithAccounts(secret, accountStatusFunc(func(string) (string, error) { return domain.RoleUser, nil }))
+}
+
+func setupRouterWithAccounts(secret domain.Secret, accounts domain.AccountStatusUsecase) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
-	r.Use(JwtAuthMiddleware(secret))
+	r.Use(ErrorMiddleware(), JwtAuthMiddleware(secret, accounts))
 	r.GET("/protected", func(c *gin.Context) {
 		userID := c.GetString("x-user-id")
 		c.JSON(http.StatusOK, gin.H{"userId": userID})
@@ -131,3 +148,90 @@ func TestJwtAuthMiddleware_MissingHeader(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 	assert.Contains(t, w.Body.String(), "missing or invalid authorization header")
 }
+
+func TestJwtAuthMiddleware_RotatedSecret(t *testing.T) {
+	secret := secrets.Static(testSecret)
+	router := setupRouterWithSecret(secret)
+
+	claims := &domain.JwtCustomClaims{
+		Name: "Test User",
+		ID:   "123",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	oldToken := createTestToken(claims, testSecret)
+	secret.Set("rotated-secret-key")
+	newToken := createTestToken(claims, "rotated-secret-key")
+	secret.Set("another-secret-key")
+
+	for name, tc := range map[string]struct {
+		token string
+		code  int
+	}{
+		"previous secret still accepted":  {newToken, http.StatusOK},
+		"secret before previous rejected": {oldToken, http.StatusUnauthorized},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/protected", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.code, w.Code)
+		})
+	}
+}
+
+func TestJwtAuthMiddleware_DisabledUser(t *testing.T) {
+	router := setupRouterWithAccounts(secrets.Static(testSecret), accountStatusFunc(func(userID string) (string, error) {
+		if userID == "123" {
+			return "", domain.ErrUserDisabled
+		}
+		return domain.RoleUser, nil
+	}))
+
+	claims := &domain.JwtCustomClaims{
+		Name: "Disabled User",
+		ID:   "123",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token := createTestToken(claims, testSecret)
+
+	req, _ := http.NewRequest("GET", "/protected", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code, "still-valid token of a disabled user is rejected")
+	assert.Contains(t, w.Body.String(), string(domain.CodeUserDisabled))
+}
+
+func TestJwtAuthMiddleware_DemotedAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// 数据库中的角色已被降级为 user，token 中仍声明 admin
+	accounts := accountStatusFunc(func(string) (string, error) { return domain.RoleUser, nil })
+	r.Use(ErrorMiddleware(), JwtAuthMiddleware(secrets.Static(testSecret), accounts), RequireRole(domain.RoleAdmin))
+	r.GET("/admin", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	claims := &domain.JwtCustomClaims{
+		Name: "Former Admin",
+		ID:   "123",
+		Role: domain.RoleAdmin,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	req, _ := http.NewRequest("GET", "/admin", nil)
+	req.Header.Set("Authorization", "Bearer "+createTestToken(claims, testSecret))
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code, "role claim of a still-valid token is ignored after demotion")
+}
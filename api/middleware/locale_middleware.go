@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/internal/i18n"
+)
+
+// LocaleMiddleware 按 Accept-Language 协商响应语言并放入请求 ctx，后续可通过 i18n.FromContext 读取
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag := i18n.Match(c.GetHeader("Accept-Language"))
+		c.Request = c.Request.WithContext(i18n.NewContext(c.Request.Context(), tag))
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Header("Content-Language", tag.String())
+
+		c.Next()
+	}
+}
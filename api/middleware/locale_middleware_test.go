@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/dto"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(LocaleMiddleware(), ErrorMiddleware())
+	r.POST("/signup", func(c *gin.Context) {
+		_ = c.Error(domain.ErrValidation.WithFields(
+			domain.FieldError{Field: "password", Rule: "min", Param: "6", Message: "must be at least 6 characters"},
+			domain.FieldError{Field: "nickname", Rule: "custom", Message: "is invalid"},
+		))
+	})
+
+	serve := func(acceptLanguage string) (*httptest.ResponseRecorder, dto.Problem) {
+		req := httptest.NewRequest(http.MethodPost, "/signup", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var problem dto.Problem
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		return w, problem
+	}
+
+	t.Run("zh", func(t *testing.T) {
+		w, problem := serve("zh-CN,zh;q=0.9")
+
+		assert.Equal(t, "zh", w.Header().Get("Content-Language"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Language")
+		assert.Equal(t, string(domain.CodeValidationFailed), problem.Code)
+		assert.Equal(t, "请求参数校验失败", problem.Detail)
+		require.Len(t, problem.Errors, 2)
+		assert.Equal(t, "长度不能少于 6 个字符", problem.Errors[0].Message)
+		assert.Equal(t, "无效", problem.Errors[1].Message)
+	})
+
+	t.Run("default_en", func(t *testing.T) {
+		w, problem := serve("")
+
+		assert.Equal(t, "en", w.Header().Get("Content-Language"))
+		assert.Equal(t, "request validation failed", problem.Detail)
+		require.Len(t, problem.Errors, 2)
+		assert.Equal(t, "must be at least 6 characters", problem.Errors[0].Message)
+	})
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/ratelimit"
+)
+
+// RateLimitMiddleware 按客户端 IP 限流，超出时返回 429
+func RateLimitMiddleware(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !limiter.Allow(c.ClientIP()) {
+			_ = c.Error(domain.ErrRateLimited)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+	"github.com/horaoen/go-backend-clean-architecture/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorMiddleware(), RateLimitMiddleware(ratelimit.New(dynconf.NewValue(ratelimit.Config{RPS: 0.001, Burst: 2}))))
+	r.POST("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+		codes = append(codes, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestRateLimitMiddleware_SpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(nil))
+	r.Use(ErrorMiddleware(), RateLimitMiddleware(ratelimit.New(dynconf.NewValue(ratelimit.Config{RPS: 0.001, Burst: 1}))))
+	r.POST("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	var codes []int
+	for _, forwardedFor := range []string{"203.0.113.1", "203.0.113.2"} {
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = "198.51.100.7:40000"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, codes, "spoofed X-Forwarded-For does not get a fresh bucket")
+}
+
+func TestRequireFeature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	flags := dynconf.NewValue(domain.FeatureFlags{})
+	r := gin.New()
+	r.Use(ErrorMiddleware())
+	r.GET("/beta", RequireFeature(flags, "beta"), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/beta", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	flags.Store(domain.FeatureFlags{"beta": true})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/beta", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
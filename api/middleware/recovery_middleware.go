@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"io"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// RecoveryMiddleware 捕获 handler 中的 panic，使用请求 logger 记录堆栈，由 ErrorMiddleware 返回 500
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		log.Ctx(c.Request.Context()).Error().
+			Interface("panic", recovered).
+			Bytes("stack", debug.Stack()).
+			Msg("panic recovered")
+		_ = c.Error(domain.ErrInternalServer)
+		c.Abort()
+	})
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/internal/requestid"
+	"github.com/rs/zerolog/log"
+)
+
+// RequestIDMiddleware 沿用合法的上游 X-Request-ID 或生成新的 ID，写入响应头，
+// 并在请求 ctx 中放入带 request_id 字段的 logger，后续层可通过 log.Ctx(ctx) 使用
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+		c.Set("x-request-id", id)
+		c.Header(requestid.Header, id)
+
+		ctx := requestid.NewContext(c.Request.Context(), id)
+		logger := log.Logger.With().Str("request_id", id).Ctx(ctx).Logger()
+		c.Request = c.Request.WithContext(logger.WithContext(ctx))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+// RequireRole 要求当前用户具有 role 角色，需放在 JwtAuthMiddleware 之后；
+// 角色取自 JwtAuthMiddleware 从数据库读到的当前值，而非 token 中的声明
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("x-user-role") != role {
+			_ = c.Error(domain.ErrForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
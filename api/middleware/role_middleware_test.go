@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		role     string
+		expected int
+	}{
+		{"admin", domain.RoleAdmin, http.StatusOK},
+		{"user", domain.RoleUser, http.StatusForbidden},
+		{"missing", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(ErrorMiddleware(), func(c *gin.Context) {
+				if tt.role != "" {
+					c.Set("x-user-role", tt.role)
+				}
+			}, RequireRole(domain.RoleAdmin))
+			r.GET("/admin", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+			assert.Equal(t, tt.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/controller"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+// NewAdminRouter 注册仅管理员可访问的运维接口，group 需已启用 JWT 鉴权
+func NewAdminRouter(logLevelManager domain.LogLevelManager, group *gin.RouterGroup) {
+	lc := &controller.LogLevelController{
+		LogLevelManager: logLevelManager,
+	}
+	group.GET("/admin/log-level", lc.Get)
+	group.PUT("/admin/log-level", lc.Set)
+}
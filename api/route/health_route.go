@@ -0,0 +1,15 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/controller"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+func NewHealthRouter(healthRegistry domain.HealthRegistry, group *gin.RouterGroup) {
+	hc := &controller.HealthController{
+		HealthRegistry: healthRegistry,
+	}
+	group.GET("/healthz", hc.Liveness)
+	group.GET("/readyz", hc.Readiness)
+}
@@ -6,12 +6,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/horaoen/go-backend-clean-architecture/api/controller"
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/metrics"
+	"github.com/horaoen/go-backend-clean-architecture/internal/tracing"
 	"github.com/horaoen/go-backend-clean-architecture/usecase"
 )
 
-func NewLoginRouter(userRepo domain.UserRepository, tokenService domain.TokenService, timeout time.Duration, group *gin.RouterGroup) {
+func NewLoginRouter(userRepo domain.UserRepository, tokenService domain.TokenService, m *metrics.Metrics, timeout time.Duration, group *gin.RouterGroup) {
 	lc := &controller.LoginController{
-		LoginUsecase: usecase.NewLoginUsecase(userRepo, tokenService, timeout),
+		LoginUsecase: metrics.InstrumentLogin(m, tracing.TraceLogin(usecase.NewLoginUsecase(userRepo, tokenService, timeout))),
 	}
 	group.POST("/login", lc.Login)
 }
@@ -0,0 +1,17 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/internal/metrics"
+)
+
+// NewMetricsHandler 返回只提供 /metrics 的 handler，由独立的内部监听地址（METRICS_ADDRESS）提供，
+// 不挂在对外的业务路由上
+func NewMetricsHandler(m *metrics.Metrics) http.Handler {
+	engine := gin.New()
+	engine.Use(gin.Recovery())
+	engine.GET("/metrics", gin.WrapH(m.Handler()))
+	return engine
+}
@@ -5,14 +5,34 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/horaoen/go-backend-clean-architecture/api/controller"
+	"github.com/horaoen/go-backend-clean-architecture/api/middleware"
+	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+	"github.com/horaoen/go-backend-clean-architecture/internal/tracing"
 	"github.com/horaoen/go-backend-clean-architecture/usecase"
 )
 
-func NewProfileRouter(userRepo domain.UserRepository, timeout time.Duration, group *gin.RouterGroup) {
+// emailConfirmPath 为邮箱变更确认接口的路径，邮件中的链接固定指向 v1 下的该路径，不随旧路由变化
+const emailConfirmPath = "/profile/email/confirm"
+
+// NewProfileRouter 注册个人资料接口，除头像上传外均使用 bodyLimit 限制请求体；
+// 头像上传受功能开关 domain.FeatureAvatarUpload 控制
+func NewProfileRouter(userRepo domain.UserRepository, txManager domain.TxManager, tokenService domain.TokenService, mailer domain.Mailer, blobStore domain.BlobStore, env *bootstrap.Env, features *dynconf.Value[domain.FeatureFlags], timeout time.Duration, publicGroup *gin.RouterGroup, group *gin.RouterGroup, bodyLimit gin.HandlerFunc) {
 	pc := &controller.ProfileController{
-		ProfileUsecase: usecase.NewProfileUsecase(userRepo, timeout),
+		ProfileUsecase: tracing.TraceProfile(usecase.NewProfileUsecase(userRepo, txManager, tokenService, mailer, blobStore, env.AppBaseURL+v1Prefix+emailConfirmPath, timeout, time.Duration(env.AvatarTimeoutSecond)*time.Second)),
+		AvatarMaxBytes: int64(env.AvatarMaxSizeKB) << 10,
 	}
-	group.GET("/profile", pc.Fetch)
-	group.POST("/profile/change-password", pc.ChangePassword)
+	// 确认链接只打开页面，由页面以 POST 提交，避免邮件扫描和链接预取触发变更
+	publicGroup.GET(emailConfirmPath, pc.ConfirmEmailChangePage)
+	publicGroup.POST(emailConfirmPath, bodyLimit, pc.ConfirmEmailChange)
+	group.PUT("/profile/avatar", middleware.RequireFeature(features, domain.FeatureAvatarUpload), pc.UploadAvatar)
+
+	limited := group.Group("", bodyLimit)
+	limited.GET("/profile", pc.Fetch)
+	limited.PATCH("/profile", pc.Update)
+	limited.POST("/profile/email", pc.RequestEmailChange)
+	limited.POST("/profile/change-password", pc.ChangePassword)
+	limited.DELETE("/profile", pc.DeleteAccount)
+	limited.GET("/profile/export", pc.Export)
 }
@@ -6,12 +6,14 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/horaoen/go-backend-clean-architecture/api/controller"
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/metrics"
+	"github.com/horaoen/go-backend-clean-architecture/internal/tracing"
 	"github.com/horaoen/go-backend-clean-architecture/usecase"
 )
 
-func NewRefreshTokenRouter(userRepo domain.UserRepository, tokenService domain.TokenService, timeout time.Duration, group *gin.RouterGroup) {
+func NewRefreshTokenRouter(userRepo domain.UserRepository, tokenService domain.TokenService, m *metrics.Metrics, timeout time.Duration, group *gin.RouterGroup) {
 	rtc := &controller.RefreshTokenController{
-		RefreshTokenUsecase: usecase.NewRefreshTokenUsecase(userRepo, tokenService, timeout),
+		RefreshTokenUsecase: metrics.InstrumentRefreshToken(m, tracing.TraceRefreshToken(usecase.NewRefreshTokenUsecase(userRepo, tokenService, timeout))),
 	}
 	group.POST("/refresh", rtc.RefreshToken)
 }
@@ -4,32 +4,60 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/dto"
 	"github.com/horaoen/go-backend-clean-architecture/api/middleware"
 	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
-	_ "github.com/horaoen/go-backend-clean-architecture/docs"
-	"github.com/horaoen/go-backend-clean-architecture/repository"
-	"github.com/horaoen/go-backend-clean-architecture/usecase"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
-	"gorm.io/gorm"
+	"github.com/horaoen/go-backend-clean-architecture/internal/ratelimit"
 )
 
-func Setup(env *bootstrap.Env, timeout time.Duration, db *gorm.DB, gin *gin.Engine) {
-	userRepo := repository.NewUserRepository(db)
-	tokenService := usecase.NewTokenService(
-		env.AccessTokenSecret,
-		env.RefreshTokenSecret,
-		env.AccessTokenExpiryHour,
-		env.RefreshTokenExpiryHour,
+const v1Prefix = "/api/v1"
+
+// legacyRoutesDeprecatedAt 为引入 /api/v1 前缀、根路径旧路由开始弃用的时间
+var legacyRoutesDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+func Setup(app *bootstrap.Application, timeout time.Duration, gin *gin.Engine) {
+	env := app.Env
+
+	dto.ConfigureBinding()
+	// handler 误把 *gin.Context 当作 ctx 传给下层时，也能取到请求 ctx 中的 span、logger 和取消信号
+	gin.ContextWithFallback = true
+
+	// ErrorMiddleware 需位于 Metrics 之内、Recovery 之外，指标和日志才能记录到最终状态码
+	gin.Use(
+		middleware.TracingMiddleware(),
+		middleware.RequestIDMiddleware(),
+		middleware.AccessLogMiddleware(middleware.AccessLogConfig{
+			RedactFields: bootstrap.SplitList(env.AccessLogRedactFields),
+			Headers:      bootstrap.SplitList(env.AccessLogHeaders),
+		}),
+		middleware.MetricsMiddleware(app.Metrics),
+		middleware.LocaleMiddleware(),
+		middleware.ErrorMiddleware(),
+		middleware.RecoveryMiddleware(),
+		middleware.CORSMiddleware(app.Runtime.CORSOrigins),
 	)
+	gin.NoRoute(middleware.NotFound)
 
 	publicRouter := gin.Group("")
-	publicRouter.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	NewSignupRouter(userRepo, tokenService, timeout, publicRouter)
-	NewLoginRouter(userRepo, tokenService, timeout, publicRouter)
-	NewRefreshTokenRouter(userRepo, tokenService, timeout, publicRouter)
-
-	protectedRouter := gin.Group("")
-	protectedRouter.Use(middleware.JwtAuthMiddleware(env.AccessTokenSecret))
-	NewProfileRouter(userRepo, timeout, protectedRouter)
+	// 接口文档按版本提供，由 swag init -g cmd/main.go -o docs/v1 --instanceName v1 --tags '!Health' 生成；
+	// 探针接口不属于 /api/v1，不写入文档
+	NewSwaggerRouter(legacyRoutesDeprecatedAt, publicRouter)
+	NewHealthRouter(app.Health, publicRouter)
+	if env.BlobStore == "" || env.BlobStore == "local" {
+		publicRouter.Static("/uploads", env.BlobLocalDir)
+	}
+
+	// 各版本共享同一个限流器，避免客户端借旧路由获得双倍配额
+	limiter := ratelimit.New(app.Runtime.RateLimit)
+	NewV1Router(app, limiter, timeout, gin.Group(v1Prefix))
+
+	// 根路径下的旧路由沿用 v1 的 handler，仅增加弃用响应头，确认客户端迁移后用 LEGACY_ROUTES=false 关闭
+	if env.LegacyRoutes {
+		deprecation := middleware.DeprecationConfig{Since: legacyRoutesDeprecatedAt, SuccessorPrefix: v1Prefix}
+		if env.LegacyRoutesSunset != "" {
+			// 已由 Env.Validate 校验格式
+			deprecation.Sunset, _ = time.Parse(time.DateOnly, env.LegacyRoutesSunset)
+		}
+		NewV1Router(app, limiter, timeout, gin.Group("", middleware.DeprecationMiddleware(deprecation)))
+	}
 }
@@ -6,12 +6,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/horaoen/go-backend-clean-architecture/api/controller"
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/tracing"
 	"github.com/horaoen/go-backend-clean-architecture/usecase"
 )
 
-func NewSignupRouter(userRepo domain.UserRepository, tokenService domain.TokenService, timeout time.Duration, group *gin.RouterGroup) {
+func NewSignupRouter(userRepo domain.UserRepository, tokenService domain.TokenService, mailer domain.Mailer, concealExisting bool, timeout time.Duration, group *gin.RouterGroup) {
 	sc := controller.SignupController{
-		SignupUsecase: usecase.NewSignupUsecase(userRepo, tokenService, timeout),
+		SignupUsecase: tracing.TraceSignup(usecase.NewSignupUsecase(userRepo, tokenService, mailer, concealExisting, timeout)),
 	}
 	group.POST("/signup", sc.Signup)
 }
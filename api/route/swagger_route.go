@@ -0,0 +1,35 @@
+package route
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/middleware"
+	v1docs "github.com/horaoen/go-backend-clean-architecture/docs/v1"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+const swaggerV1Prefix = "/swagger/v1"
+
+// NewSwaggerRouter 在 /swagger/v1 下提供 v1 接口文档。引入版本前缀前的 /swagger 地址保留为弃用别名，
+// 返回同一份文档并附带 Deprecation 头和指向新地址的 Link 头
+func NewSwaggerRouter(deprecatedSince time.Time, group *gin.RouterGroup) {
+	// gin 不允许 /swagger/*any 与 /swagger/v1/*any 同时注册，只能在同一个通配路由内区分新旧地址
+	group.GET("/swagger/*any", legacySwaggerHeaders(deprecatedSince),
+		ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.InstanceName(v1docs.SwaggerInfov1.InstanceName())))
+}
+
+func legacySwaggerHeaders(since time.Time) gin.HandlerFunc {
+	deprecated := middleware.DeprecationMiddleware(middleware.DeprecationConfig{Since: since})
+	return func(c *gin.Context) {
+		path := c.Param("any")
+		if strings.HasPrefix(path, "/v1/") {
+			c.Next()
+			return
+		}
+		c.Header("Link", "<"+swaggerV1Prefix+path+`>; rel="successor-version"`)
+		deprecated(c)
+	}
+}
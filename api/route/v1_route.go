@@ -0,0 +1,39 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/middleware"
+	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/logging"
+	"github.com/horaoen/go-backend-clean-architecture/internal/ratelimit"
+	"github.com/horaoen/go-backend-clean-architecture/repository"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+)
+
+// NewV1Router 在 group 下注册 v1 业务接口。新增 v2 时在 api/dto/v2 定义 DTO，为有变化的接口编写
+// v2 controller 并在 NewV2Router 中注册，未变化的接口直接复用 v1 的 New*Router，v1 保持不变
+func NewV1Router(app *bootstrap.Application, limiter *ratelimit.Limiter, timeout time.Duration, group *gin.RouterGroup) {
+	env := app.Env
+	userRepo := repository.NewUserRepository(app.DB)
+	tokenService := app.TokenService
+
+	// 业务接口协商请求和响应格式并限制请求体大小，头像上传由 ProfileController 按 AVATAR_MAX_SIZE_KB 自行限制
+	apiRouter := group.Group("", middleware.ContentNegotiationMiddleware())
+	bodyLimit := middleware.BodyLimitMiddleware(int64(env.ServerMaxBodyKB) << 10)
+
+	// 认证接口按客户端 IP 限流，限流参数可热更新
+	authRouter := apiRouter.Group("", middleware.RateLimitMiddleware(limiter), bodyLimit)
+	NewSignupRouter(userRepo, tokenService, app.Mailer, env.SignupConcealExisting, timeout, authRouter)
+	NewLoginRouter(userRepo, tokenService, app.Metrics, timeout, authRouter)
+	NewRefreshTokenRouter(userRepo, tokenService, app.Metrics, timeout, authRouter)
+
+	protectedRouter := apiRouter.Group("")
+	protectedRouter.Use(middleware.JwtAuthMiddleware(app.JWTSecrets.Access, usecase.NewAccountStatusUsecase(userRepo, timeout)))
+	NewProfileRouter(userRepo, repository.NewTxManager(app.DB), tokenService, app.Mailer, app.BlobStore, env, app.Runtime.FeatureFlags, timeout, apiRouter, protectedRouter, bodyLimit)
+
+	adminRouter := protectedRouter.Group("", bodyLimit, middleware.RequireRole(domain.RoleAdmin))
+	NewAdminRouter(logging.NewLevelManager(), adminRouter)
+}
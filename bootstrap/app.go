@@ -2,31 +2,102 @@
 package bootstrap
 
 import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/mailer"
+	"github.com/horaoen/go-backend-clean-architecture/internal/metrics"
+	"github.com/horaoen/go-backend-clean-architecture/internal/secrets"
 	"github.com/horaoen/go-backend-clean-architecture/repository/model"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+	zlog "github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
 type Application struct {
-	Env *Env
-	DB  *gorm.DB
+	Env          *Env
+	DB           *gorm.DB
+	Mailer       domain.Mailer
+	BlobStore    domain.BlobStore
+	TokenService domain.TokenService
+	// Health 为就绪探针的检查注册表，其他组件可在启动时追加自己的检查
+	Health  domain.HealthRegistry
+	Metrics *metrics.Metrics
+	// JWTSecrets 由 SecretRefresher 定期刷新，未配置 SECRET_PROVIDER 时 SecretRefresher 为 nil
+	JWTSecrets      JWTSecrets
+	SecretRefresher *secrets.Refresher
+	// Runtime 为可热更新的配置，由 WatchConfig 在配置文件变化时更新
+	Runtime *RuntimeSettings
+
+	envOptions EnvOptions
+
+	shutdownTracing func(context.Context) error
+	logCloser       io.Closer
 }
 
-func App() Application {
-	app := &Application{}
-	app.Env = NewEnv()
-	InitLog(app.Env)
+// tracingFlushTimeout 为退出时导出剩余 span 的最长等待时间
+const tracingFlushTimeout = 5 * time.Second
 
-	app.DB = NewPostgres(app.Env)
+func App(opts EnvOptions) Application {
+	app := &Application{envOptions: opts}
+	app.Env = NewEnv(opts)
+	app.Runtime = NewRuntimeSettings(app.Env)
+	app.logCloser = InitLog(app.Env)
 
-	// 自动迁移数据库表
-	err := app.DB.AutoMigrate(&model.UserModel{})
+	app.DB = NewDatabase(app.Env)
+	app.Metrics = NewMetrics(app.DB)
+	app.shutdownTracing = NewTracing(app.Env, app.DB)
+	app.Mailer = mailer.NewLogMailer()
+	app.BlobStore = NewBlobStore(app.Env)
+	app.JWTSecrets = NewJWTSecrets(app.Env)
+	refresher, err := NewSecretRefresher(app.Env, app.JWTSecrets)
 	if err != nil {
-		panic("数据库迁移失败: " + err.Error())
+		zlog.Fatal().Err(err).Msg("secret provider init fail")
+	}
+	app.SecretRefresher = refresher
+	app.TokenService = metrics.InstrumentTokenService(app.Metrics, usecase.NewTokenService(
+		app.JWTSecrets.Access,
+		app.JWTSecrets.Refresh,
+		app.JWTSecrets.Email,
+		app.Runtime.TokenLifetimes.Load,
+	))
+
+	// 表结构由 migrate 子命令管理，AutoMigrate 仅作为开发环境的便捷选项
+	if app.Env.AppEnv == "development" && app.Env.DBAutoMigrate {
+		if err := app.DB.AutoMigrate(&model.UserModel{}); err != nil {
+			zlog.Fatal().Err(err).Msg("数据库自动迁移失败")
+		}
 	}
 
+	app.Health = NewHealthRegistry(app.DB)
+
 	return *app
 }
 
+// WatchConfig 监听配置文件并热更新 Runtime，未使用配置文件时不做任何事
+func (app *Application) WatchConfig() {
+	if NewConfigReloader(app.envOptions, app.Env, app.Runtime).Watch() {
+		zlog.Info().Msg("配置热更新已启用")
+	}
+}
+
+// Close 依次刷新未导出的 span、关闭数据库连接和日志文件
+func (app *Application) Close() {
+	if app.shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingFlushTimeout)
+		defer cancel()
+		if err := app.shutdownTracing(ctx); err != nil {
+			zlog.Err(err).Msg("刷新链路追踪数据失败")
+		}
+	}
+	app.CloseDBConnection()
+	if app.logCloser != nil {
+		_ = app.logCloser.Close()
+	}
+}
+
 func (app *Application) CloseDBConnection() {
 	if app.DB != nil {
 		sqlDB, err := app.DB.DB()
@@ -0,0 +1,35 @@
+package bootstrap
+
+import (
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/blobstore"
+	zlog "github.com/rs/zerolog/log"
+)
+
+func NewBlobStore(env *Env) domain.BlobStore {
+	publicURL := env.BlobPublicURL
+	if publicURL == "" && env.BlobStore != "s3" {
+		publicURL = env.AppBaseURL + "/uploads"
+	}
+
+	switch env.BlobStore {
+	case "s3":
+		store, err := blobstore.NewS3BlobStore(blobstore.S3Config{
+			Endpoint:  env.S3Endpoint,
+			Region:    env.S3Region,
+			Bucket:    env.S3Bucket,
+			AccessKey: env.S3AccessKey,
+			SecretKey: env.S3SecretKey,
+			PublicURL: publicURL,
+		}, nil)
+		if err != nil {
+			zlog.Fatal().Err(err).Msg("s3 blob store init fail")
+		}
+		return store
+	case "", "local":
+		return blobstore.NewLocalBlobStore(env.BlobLocalDir, publicURL)
+	default:
+		zlog.Fatal().Str("blobStore", env.BlobStore).Msg("unknown blob store")
+		return nil
+	}
+}
@@ -1,20 +1,89 @@
 package bootstrap
 
 import (
-	"fmt"
+	"context"
+	"time"
 
+	"github.com/horaoen/go-backend-clean-architecture/internal/database"
+	"github.com/horaoen/go-backend-clean-architecture/internal/gormlog"
 	zlog "github.com/rs/zerolog/log"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func NewPostgres(env *Env) *gorm.DB {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai",
-		env.PostgresHost, env.PostgresUser, env.PostgresPassword, env.PostgresDB, env.PostgresPort)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+const (
+	// slowQueryThreshold 为 SQL 慢查询日志阈值
+	slowQueryThreshold = 200 * time.Millisecond
+	// 启动时连接数据库失败后的重试间隔，按指数增长
+	connectInitialBackoff = 500 * time.Millisecond
+	connectMaxBackoff     = 10 * time.Second
+)
+
+// NewDatabase 按 DB_DRIVER 连接数据库，在 DB_CONNECT_TIMEOUT_SECOND 内重试，仍失败时退出进程
+func NewDatabase(env *Env) *gorm.DB {
+	primary, replicas, err := databaseDialectors(env)
 	if err != nil {
-		zlog.Fatal().AnErr("postgres connect fail", err)
+		zlog.Fatal().Err(err).Msg("数据库配置无效")
+	}
+	pool := database.PoolConfig{
+		MaxOpenConns:    env.DBMaxOpenConns,
+		MaxIdleConns:    env.DBMaxIdleConns,
+		ConnMaxLifetime: time.Duration(env.DBConnMaxLifetimeSecond) * time.Second,
+		ConnMaxIdleTime: time.Duration(env.DBConnMaxIdleTimeSecond) * time.Second,
 	}
-	zlog.Info().Msg("PostgreSQL 连接成功")
+
+	var db *gorm.DB
+	err = database.Retry(context.Background(), database.RetryConfig{
+		MaxElapsed:     time.Duration(env.DBConnectTimeoutSecond) * time.Second,
+		InitialBackoff: connectInitialBackoff,
+		MaxBackoff:     connectMaxBackoff,
+		OnRetry: func(attempt int, err error, wait time.Duration) {
+			zlog.Warn().Err(err).Int("attempt", attempt).Dur("retry_in", wait).Msg("数据库连接失败，稍后重试")
+		},
+	}, func() error {
+		var err error
+		db, err = database.Open(primary, replicas, pool, &gorm.Config{
+			Logger: gormlog.New(slowQueryThreshold),
+		})
+		return err
+	})
+	if err != nil {
+		zlog.Fatal().Err(err).Str("driver", env.DBDriver).Msg("数据库连接失败")
+	}
+	zlog.Info().Str("driver", env.DBDriver).Int("replicas", len(replicas)).Msg("数据库连接成功")
 	return db
 }
+
+func databaseDialectors(env *Env) (gorm.Dialector, []gorm.Dialector, error) {
+	cfg := databaseConfig(env)
+	primary, err := database.Dialector(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	replicaConfigs, err := database.ReplicaConfigs(cfg, SplitList(env.DBReplicaHosts))
+	if err != nil {
+		return nil, nil, err
+	}
+	replicas := make([]gorm.Dialector, len(replicaConfigs))
+	for i, replicaConfig := range replicaConfigs {
+		if replicas[i], err = database.Dialector(replicaConfig); err != nil {
+			return nil, nil, err
+		}
+	}
+	return primary, replicas, nil
+}
+
+func databaseConfig(env *Env) database.Config {
+	return database.Config{
+		Driver:      env.DBDriver,
+		DSN:         env.DBDSN,
+		Host:        env.DBHost,
+		Port:        env.DBPort,
+		Name:        env.DBName,
+		User:        env.DBUser,
+		Password:    env.DBPassword,
+		SSLMode:     env.DBSSLMode,
+		SSLRootCert: env.DBSSLRootCert,
+		TimeZone:    env.DBTimeZone,
+	}
+}
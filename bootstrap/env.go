@@ -1,47 +1,325 @@
 package bootstrap
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
 	"log"
+	"os"
+	"reflect"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
 type Env struct {
-	AppEnv         string `mapstructure:"APP_ENV"`
-	ServerAddress  string `mapstructure:"SERVER_ADDRESS"`
-	ContextTimeout int    `mapstructure:"CONTEXT_TIMEOUT"`
-	LogLevel       int    `mapstructure:"LOG_LEVEL"`
-	// PostgreSQL Configuration
-	PostgresHost     string `mapstructure:"POSTGRES_HOST"`
-	PostgresPort     string `mapstructure:"POSTGRES_PORT"`
-	PostgresDB       string `mapstructure:"POSTGRES_DB"`
-	PostgresUser     string `mapstructure:"POSTGRES_USER"`
-	PostgresPassword string `mapstructure:"POSTGRES_PASSWORD"`
+	AppEnv         string `mapstructure:"APP_ENV" validate:"required,oneof=development test staging production"`
+	ServerAddress  string `mapstructure:"SERVER_ADDRESS" validate:"required"`
+	AppBaseURL     string `mapstructure:"APP_BASE_URL" validate:"required,url"`
+	ContextTimeout int    `mapstructure:"CONTEXT_TIMEOUT" validate:"min=1"`
+	LogLevel       int    `mapstructure:"LOG_LEVEL" validate:"min=-1,max=7"`
+	// Log Output Configuration，LOG_FORMAT 为空时开发环境使用 console，其余环境使用 json
+	LogFormat             string `mapstructure:"LOG_FORMAT" validate:"omitempty,oneof=json console"`
+	LogFile               string `mapstructure:"LOG_FILE"`
+	LogFileMaxSizeMB      int    `mapstructure:"LOG_FILE_MAX_SIZE_MB" validate:"min=1"`
+	LogFileMaxAgeDay      int    `mapstructure:"LOG_FILE_MAX_AGE_DAY" validate:"min=0"`
+	LogFileMaxBackups     int    `mapstructure:"LOG_FILE_MAX_BACKUPS" validate:"min=0"`
+	LogSampleBurst        int    `mapstructure:"LOG_SAMPLE_BURST" validate:"min=0"`
+	LogSamplePeriodSecond int    `mapstructure:"LOG_SAMPLE_PERIOD_SECOND" validate:"min=1"`
+	LogSampleThereafter   int    `mapstructure:"LOG_SAMPLE_THEREAFTER" validate:"min=1"`
+	// Access Log Configuration，多个值以逗号分隔
+	AccessLogRedactFields string `mapstructure:"ACCESS_LOG_REDACT_FIELDS"`
+	AccessLogHeaders      string `mapstructure:"ACCESS_LOG_HEADERS"`
+	// Tracing Configuration
+	TracingExporter     string  `mapstructure:"TRACING_EXPORTER" validate:"oneof=none stdout otlp"`
+	TracingServiceName  string  `mapstructure:"TRACING_SERVICE_NAME" validate:"required"`
+	TracingSampleRatio  float64 `mapstructure:"TRACING_SAMPLE_RATIO" validate:"min=0,max=1"`
+	TracingOTLPEndpoint string  `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure bool    `mapstructure:"TRACING_OTLP_INSECURE"`
+	// HTTP Server Configuration
+	ServerReadTimeoutSecond  int `mapstructure:"SERVER_READ_TIMEOUT_SECOND" validate:"min=0"`
+	ServerWriteTimeoutSecond int `mapstructure:"SERVER_WRITE_TIMEOUT_SECOND" validate:"min=0"`
+	ServerIdleTimeoutSecond  int `mapstructure:"SERVER_IDLE_TIMEOUT_SECOND" validate:"min=0"`
+	// ServerMaxBodyKB 限制业务接口的请求体大小，头像上传按 AVATAR_MAX_SIZE_KB 单独限制
+	ServerMaxBodyKB int `mapstructure:"SERVER_MAX_BODY_KB" validate:"min=1"`
+	// MetricsAddress 为 /metrics 的内部监听地址，应只对监控系统开放；为空时不提供指标接口
+	MetricsAddress string `mapstructure:"METRICS_ADDRESS"`
+	// TrustedProxies 为逗号分隔的反向代理 IP 或 CIDR，只有来自这些地址的 X-Forwarded-For 才用于识别客户端 IP；
+	// 为空时不信任任何代理，直接使用连接的对端地址
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"`
+	// LegacyRoutes 在 /api/v1 之外继续于根路径提供未加版本前缀的旧路由，响应带 Deprecation 头；
+	// LegacyRoutesSunset 为旧路由计划下线日期（YYYY-MM-DD），设置后同时返回 Sunset 头
+	LegacyRoutes       bool   `mapstructure:"LEGACY_ROUTES"`
+	LegacyRoutesSunset string `mapstructure:"LEGACY_ROUTES_SUNSET" validate:"omitempty,datetime=2006-01-02"`
+	// ShutdownDrainSecond 为收到停止信号后就绪探针失败、仍继续处理请求的时间
+	ShutdownDrainSecond   int `mapstructure:"SHUTDOWN_DRAIN_SECOND" validate:"min=0"`
+	ShutdownTimeoutSecond int `mapstructure:"SHUTDOWN_TIMEOUT_SECOND" validate:"min=1"`
+	// Database Configuration，DB_DRIVER 可选 postgres、mysql、sqlite；sqlite 下 DB_NAME 为数据库文件路径。
+	// DB_DSN 非空时直接作为连接串，忽略其余连接参数。旧的 POSTGRES_* 配置名仍可使用
+	DBDriver      string `mapstructure:"DB_DRIVER" validate:"oneof=postgres mysql sqlite"`
+	DBDSN         string `mapstructure:"DB_DSN"`
+	DBHost        string `mapstructure:"DB_HOST"`
+	DBPort        string `mapstructure:"DB_PORT" validate:"omitempty,numeric"`
+	DBName        string `mapstructure:"DB_NAME"`
+	DBUser        string `mapstructure:"DB_USER"`
+	DBPassword    string `mapstructure:"DB_PASSWORD"`
+	DBSSLMode     string `mapstructure:"DB_SSL_MODE" validate:"omitempty,oneof=disable prefer require verify-ca verify-full"`
+	DBSSLRootCert string `mapstructure:"DB_SSL_ROOT_CERT"`
+	DBTimeZone    string `mapstructure:"DB_TIMEZONE"`
+	// Database Pool Configuration，0 表示不限制
+	DBMaxOpenConns          int `mapstructure:"DB_MAX_OPEN_CONNS" validate:"min=0"`
+	DBMaxIdleConns          int `mapstructure:"DB_MAX_IDLE_CONNS" validate:"min=0"`
+	DBConnMaxLifetimeSecond int `mapstructure:"DB_CONN_MAX_LIFETIME_SECOND" validate:"min=0"`
+	DBConnMaxIdleTimeSecond int `mapstructure:"DB_CONN_MAX_IDLE_TIME_SECOND" validate:"min=0"`
+	// DBConnectTimeoutSecond 为启动时等待数据库可用的最长时间，期间按指数退避重试
+	DBConnectTimeoutSecond int `mapstructure:"DB_CONNECT_TIMEOUT_SECOND" validate:"min=0"`
+	// DBReplicaHosts 为逗号分隔的只读副本 host[:port]，其余连接参数与主库相同
+	DBReplicaHosts string `mapstructure:"DB_REPLICA_HOSTS"`
+	// DBAutoMigrate 仅在 development 环境生效，生产环境请使用 migrate 子命令
+	DBAutoMigrate bool `mapstructure:"DB_AUTO_MIGRATE"`
 	// JWT Configuration
-	AccessTokenExpiryHour  int    `mapstructure:"ACCESS_TOKEN_EXPIRY_HOUR"`
-	RefreshTokenExpiryHour int    `mapstructure:"REFRESH_TOKEN_EXPIRY_HOUR"`
-	AccessTokenSecret      string `mapstructure:"ACCESS_TOKEN_SECRET"`
-	RefreshTokenSecret     string `mapstructure:"REFRESH_TOKEN_SECRET"`
+	AccessTokenExpiryHour  int    `mapstructure:"ACCESS_TOKEN_EXPIRY_HOUR" validate:"min=1"`
+	RefreshTokenExpiryHour int    `mapstructure:"REFRESH_TOKEN_EXPIRY_HOUR" validate:"min=1"`
+	AccessTokenSecret      string `mapstructure:"ACCESS_TOKEN_SECRET" validate:"required"`
+	RefreshTokenSecret     string `mapstructure:"REFRESH_TOKEN_SECRET" validate:"required"`
+	EmailTokenExpiryHour   int    `mapstructure:"EMAIL_TOKEN_EXPIRY_HOUR" validate:"min=1"`
+	EmailTokenSecret       string `mapstructure:"EMAIL_TOKEN_SECRET" validate:"required"`
+	// Signup Configuration
+	SignupConcealExisting bool `mapstructure:"SIGNUP_CONCEAL_EXISTING"`
+	// Account Deletion Configuration
+	AccountPurgeAfterHour      int `mapstructure:"ACCOUNT_PURGE_AFTER_HOUR" validate:"min=0"`
+	AccountPurgeIntervalMinute int `mapstructure:"ACCOUNT_PURGE_INTERVAL_MINUTE" validate:"min=0"`
+	// AccountPurgeMode 为 delete 时物理删除过期账号，为 anonymize 时保留记录并抹除个人数据
+	AccountPurgeMode string `mapstructure:"ACCOUNT_PURGE_MODE" validate:"oneof=delete anonymize"`
+	// Blob Storage Configuration
+	BlobStore       string `mapstructure:"BLOB_STORE" validate:"oneof=local s3"`
+	BlobLocalDir    string `mapstructure:"BLOB_LOCAL_DIR" validate:"required_if=BlobStore local"`
+	BlobPublicURL   string `mapstructure:"BLOB_PUBLIC_URL" validate:"omitempty,url"`
+	S3Endpoint      string `mapstructure:"S3_ENDPOINT" validate:"required_if=BlobStore s3"`
+	S3Region        string `mapstructure:"S3_REGION" validate:"required_if=BlobStore s3"`
+	S3Bucket        string `mapstructure:"S3_BUCKET" validate:"required_if=BlobStore s3"`
+	S3AccessKey     string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey     string `mapstructure:"S3_SECRET_KEY"`
+	AvatarMaxSizeKB int    `mapstructure:"AVATAR_MAX_SIZE_KB" validate:"min=1"`
+	// AvatarTimeoutSecond 为头像上传（解码、缩放和写入存储）的超时，不受 CONTEXT_TIMEOUT 限制，
+	// 应小于 SERVER_WRITE_TIMEOUT_SECOND
+	AvatarTimeoutSecond int `mapstructure:"AVATAR_TIMEOUT_SECOND" validate:"min=1"`
+	// Runtime Configuration，以下配置及 LOG_LEVEL、*_TOKEN_EXPIRY_HOUR 在配置文件修改后热更新，
+	// 无需重启；RATE_LIMIT_RPS 为每个客户端 IP 在认证接口上的每秒请求数，0 表示不限流
+	RateLimitRPS   float64 `mapstructure:"RATE_LIMIT_RPS" validate:"min=0"`
+	RateLimitBurst int     `mapstructure:"RATE_LIMIT_BURST" validate:"min=0"`
+	// CORSAllowedOrigins 与 FeatureFlags 以逗号分隔，"*" 表示允许任意来源
+	CORSAllowedOrigins string `mapstructure:"CORS_ALLOWED_ORIGINS"`
+	FeatureFlags       string `mapstructure:"FEATURE_FLAGS"`
+	// Secret Provider Configuration，启用后数据库密码和 JWT 密钥优先从 provider 读取，
+	// 其中 JWT 密钥按 SECRET_REFRESH_INTERVAL_SECOND 定期刷新
+	SecretProvider              string `mapstructure:"SECRET_PROVIDER" validate:"omitempty,oneof=none file vault"`
+	SecretDir                   string `mapstructure:"SECRET_DIR" validate:"required_if=SecretProvider file"`
+	VaultAddr                   string `mapstructure:"VAULT_ADDR" validate:"required_if=SecretProvider vault,omitempty,url"`
+	VaultToken                  string `mapstructure:"VAULT_TOKEN"`
+	VaultKVMount                string `mapstructure:"VAULT_KV_MOUNT"`
+	VaultSecretPath             string `mapstructure:"VAULT_SECRET_PATH" validate:"required_if=SecretProvider vault"`
+	SecretRefreshIntervalSecond int    `mapstructure:"SECRET_REFRESH_INTERVAL_SECOND" validate:"min=0"`
 }
 
-func NewEnv() *Env {
-	env := Env{}
-	viper.SetConfigFile(".env")
+// envDefaults 为最低优先级的默认配置
+var envDefaults = map[string]any{
+	"APP_ENV":                        "production",
+	"SERVER_ADDRESS":                 ":8080",
+	"METRICS_ADDRESS":                ":9090",
+	"APP_BASE_URL":                   "http://localhost:8080",
+	"CONTEXT_TIMEOUT":                2,
+	"LOG_LEVEL":                      1,
+	"LOG_FILE_MAX_SIZE_MB":           100,
+	"LOG_FILE_MAX_AGE_DAY":           7,
+	"LOG_FILE_MAX_BACKUPS":           10,
+	"LOG_SAMPLE_PERIOD_SECOND":       1,
+	"LOG_SAMPLE_THEREAFTER":          100,
+	"TRACING_EXPORTER":               "none",
+	"TRACING_SERVICE_NAME":           "go-backend-clean-architecture",
+	"TRACING_SAMPLE_RATIO":           1.0,
+	"SERVER_READ_TIMEOUT_SECOND":     15,
+	"SERVER_WRITE_TIMEOUT_SECOND":    30,
+	"SERVER_IDLE_TIMEOUT_SECOND":     60,
+	"SERVER_MAX_BODY_KB":             1024,
+	"LEGACY_ROUTES":                  true,
+	"SHUTDOWN_DRAIN_SECOND":          5,
+	"SHUTDOWN_TIMEOUT_SECOND":        20,
+	"DB_DRIVER":                      "postgres",
+	"DB_SSL_MODE":                    "disable",
+	"DB_TIMEZONE":                    "Asia/Shanghai",
+	"DB_MAX_OPEN_CONNS":              25,
+	"DB_MAX_IDLE_CONNS":              10,
+	"DB_CONN_MAX_LIFETIME_SECOND":    1800,
+	"DB_CONN_MAX_IDLE_TIME_SECOND":   300,
+	"DB_CONNECT_TIMEOUT_SECOND":      60,
+	"ACCESS_TOKEN_EXPIRY_HOUR":       2,
+	"REFRESH_TOKEN_EXPIRY_HOUR":      168,
+	"EMAIL_TOKEN_EXPIRY_HOUR":        24,
+	"ACCOUNT_PURGE_AFTER_HOUR":       720,
+	"ACCOUNT_PURGE_INTERVAL_MINUTE":  60,
+	"ACCOUNT_PURGE_MODE":             "delete",
+	"BLOB_STORE":                     "local",
+	"BLOB_LOCAL_DIR":                 "./uploads",
+	"AVATAR_MAX_SIZE_KB":             2048,
+	"AVATAR_TIMEOUT_SECOND":          10,
+	"RATE_LIMIT_RPS":                 5,
+	"RATE_LIMIT_BURST":               20,
+	"FEATURE_FLAGS":                  "avatar_upload",
+	"SECRET_PROVIDER":                "none",
+	"VAULT_KV_MOUNT":                 "secret",
+	"SECRET_REFRESH_INTERVAL_SECOND": 300,
+}
 
-	err := viper.ReadInConfig()
-	if err != nil {
-		log.Fatal("Can't find the file .env : ", err)
-	}
+// legacyEnvKeys 为已更名配置项的旧名称，新名称未设置时使用旧名称的值
+var legacyEnvKeys = map[string]string{
+	"DB_HOST":     "POSTGRES_HOST",
+	"DB_PORT":     "POSTGRES_PORT",
+	"DB_NAME":     "POSTGRES_DB",
+	"DB_USER":     "POSTGRES_USER",
+	"DB_PASSWORD": "POSTGRES_PASSWORD",
+}
 
-	err = viper.Unmarshal(&env)
+// EnvOptions 指定配置来源，优先级从低到高为：默认值 < 配置文件 < .env 文件 < 环境变量
+// < *_FILE 指向的密钥文件 < Overrides < SECRET_PROVIDER
+type EnvOptions struct {
+	// ConfigFile 为 YAML/TOML/JSON 配置文件路径，为空时依次查找 CONFIG_FILE 环境变量
+	// 以及 ./config.{yaml,toml,json}、./config/config.{yaml,toml,json}，均不存在时跳过
+	ConfigFile string
+	// DotEnvFile 为 .env 文件路径，为空时使用 .env，文件不存在时跳过
+	DotEnvFile string
+	// Overrides 通常来自命令行参数，键为配置名（如 SERVER_ADDRESS）
+	Overrides map[string]string
+}
+
+// NewEnv 加载并校验配置，失败时退出进程
+func NewEnv(opts EnvOptions) *Env {
+	env, err := LoadEnv(opts)
 	if err != nil {
 		log.Fatal("Environment can't be loaded: ", err)
 	}
 
 	if env.AppEnv == "development" {
 		log.Println("The App is running in development env")
-		log.Println(env)
 	}
 
-	return &env
+	return env
+}
+
+// LoadEnv 按 EnvOptions 描述的优先级合并各来源配置。校验失败时返回 *ConfigError，
+// 同时仍返回已加载的配置，便于排查
+func LoadEnv(opts EnvOptions) (*Env, error) {
+	v := viper.New()
+	for key, value := range envDefaults {
+		v.SetDefault(key, value)
+	}
+
+	if err := readConfigFile(v, opts.ConfigFile); err != nil {
+		return nil, err
+	}
+	if err := mergeDotEnv(v, opts.DotEnvFile); err != nil {
+		return nil, err
+	}
+
+	// 配置文件和 .env 中的旧名称在注册别名时迁移到新名称下
+	for key, legacy := range legacyEnvKeys {
+		v.RegisterAlias(legacy, key)
+	}
+
+	// AutomaticEnv 只对 viper 已知的键生效，Unmarshal 前需显式绑定所有键
+	v.AutomaticEnv()
+	for _, key := range envKeys() {
+		// BindEnv 的第一个参数为配置名，其余为依次查找的环境变量名
+		input := []string{key, key}
+		if legacy, ok := legacyEnvKeys[key]; ok {
+			input = append(input, legacy)
+		}
+		if err := v.BindEnv(input...); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := applySecretFiles(v); err != nil {
+		return nil, err
+	}
+
+	for key, value := range opts.Overrides {
+		v.Set(key, value)
+	}
+
+	env := Env{}
+	if err := v.Unmarshal(&env); err != nil {
+		return nil, err
+	}
+	if err := env.resolveSecrets(context.Background()); err != nil {
+		return nil, err
+	}
+	if err := env.Validate(); err != nil {
+		return &env, err
+	}
+	return &env, nil
+}
+
+func readConfigFile(v *viper.Viper, file string) error {
+	if file == "" {
+		file = os.Getenv("CONFIG_FILE")
+	}
+	if file != "" {
+		v.SetConfigFile(file)
+		if err := v.ReadInConfig(); err != nil {
+			return fmt.Errorf("read config file %s: %w", file, err)
+		}
+		return nil
+	}
+
+	v.SetConfigName("config")
+	v.AddConfigPath(".")
+	v.AddConfigPath("./config")
+	err := v.ReadInConfig()
+	var notFound viper.ConfigFileNotFoundError
+	if err != nil && !errors.As(err, &notFound) {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	return nil
+}
+
+func mergeDotEnv(v *viper.Viper, file string) error {
+	if file == "" {
+		file = ".env"
+	}
+	if _, err := os.Stat(file); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	dotEnv := viper.New()
+	dotEnv.SetConfigFile(file)
+	dotEnv.SetConfigType("env")
+	if err := dotEnv.ReadInConfig(); err != nil {
+		return fmt.Errorf("read %s: %w", file, err)
+	}
+	return v.MergeConfigMap(dotEnv.AllSettings())
+}
+
+// SplitList 解析逗号分隔的配置项，忽略空白项
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// envKeys 返回 Env 中所有配置项名称
+func envKeys() []string {
+	t := reflect.TypeOf(Env{})
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if key := t.Field(i).Tag.Get("mapstructure"); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
 }
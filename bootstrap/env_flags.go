@@ -0,0 +1,55 @@
+package bootstrap
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+)
+
+// BindEnvFlags 在 fs 上注册 --config、--env-file 以及每个配置项对应的参数（如 SERVER_ADDRESS 对应 --server-address），
+// fs 解析后返回的 EnvOptions 即包含命令行指定的值
+func BindEnvFlags(fs *flag.FlagSet) *EnvOptions {
+	opts := &EnvOptions{Overrides: map[string]string{}}
+	fs.StringVar(&opts.ConfigFile, "config", "", "path to a YAML/TOML/JSON config file")
+	fs.StringVar(&opts.DotEnvFile, "env-file", "", "path to a .env file (default .env, optional)")
+	t := reflect.TypeOf(Env{})
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+		fs.Var(&overrideValue{
+			key:       key,
+			overrides: opts.Overrides,
+			isBool:    t.Field(i).Type.Kind() == reflect.Bool,
+		}, flagName(key), "overrides "+key)
+	}
+	return opts
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+type overrideValue struct {
+	key       string
+	overrides map[string]string
+	isBool    bool
+}
+
+// IsBoolFlag 使布尔配置项可以写成 --db-auto-migrate 而不必带 =true
+func (v *overrideValue) IsBoolFlag() bool {
+	return v.isBool
+}
+
+func (v *overrideValue) String() string {
+	if v.overrides == nil {
+		return ""
+	}
+	return v.overrides[v.key]
+}
+
+func (v *overrideValue) Set(value string) error {
+	v.overrides[v.key] = value
+	return nil
+}
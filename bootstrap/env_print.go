@@ -0,0 +1,43 @@
+package bootstrap
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const redacted = "******"
+
+// secretKeySuffixes 中的 _DSN 整体掩码：连接串通常内嵌密码（user:pass@host 或 password=...）
+var secretKeySuffixes = []string{"_SECRET", "_PASSWORD", "_KEY", "_TOKEN", "_DSN"}
+
+// RedactedLines 返回按键名排序的 KEY=value 配置列表，密钥类配置的值被替换为掩码
+func (env *Env) RedactedLines() []string {
+	v := reflect.ValueOf(env).Elem()
+	t := v.Type()
+
+	lines := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+		value := fmt.Sprint(v.Field(i).Interface())
+		if isSecretKey(key) && value != "" {
+			value = redacted
+		}
+		lines = append(lines, key+"="+value)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func isSecretKey(key string) bool {
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnv_RedactedLines(t *testing.T) {
+	env := &Env{
+		AppEnv:            "production",
+		DBPassword:        "pg-pass",
+		DBDSN:             "postgres://app:dsn-pass@db:5432/app?sslmode=disable",
+		AccessTokenSecret: "access-secret",
+		S3AccessKey:       "AKIA",
+		S3Region:          "us-east-1",
+		VaultToken:        "hvs.token",
+		SecretProvider:    "vault",
+	}
+
+	lines := env.RedactedLines()
+
+	assert.Contains(t, lines, "APP_ENV=production")
+	assert.Contains(t, lines, "S3_REGION=us-east-1")
+	assert.Contains(t, lines, "DB_PASSWORD=******")
+	assert.Contains(t, lines, "DB_DSN=******")
+	assert.Contains(t, lines, "ACCESS_TOKEN_SECRET=******")
+	assert.Contains(t, lines, "S3_ACCESS_KEY=******")
+	assert.Contains(t, lines, "VAULT_TOKEN=******")
+	assert.Contains(t, lines, "SECRET_PROVIDER=vault")
+	assert.Contains(t, lines, "REFRESH_TOKEN_SECRET=")
+	assert.IsNonDecreasing(t, lines)
+	for _, line := range lines {
+		assert.NotContains(t, line, "secret")
+		assert.NotContains(t, line, "pg-pass")
+		assert.NotContains(t, line, "dsn-pass")
+	}
+}
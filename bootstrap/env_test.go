@@ -0,0 +1,188 @@
+package bootstrap
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const strongSecret = "0123456789abcdef0123456789abcdef"
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadEnv_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	configFile := writeFile(t, dir, "app.yaml", `
+app_env: development
+server_address: ":7000"
+context_timeout: 5
+log_level: 2
+postgres_host: yaml-host
+postgres_db: app
+postgres_user: app
+access_token_secret: a
+refresh_token_secret: r
+email_token_secret: e
+`)
+	writeFile(t, dir, ".env", "POSTGRES_HOST=dotenv-host\nLOG_LEVEL=3\n")
+	t.Setenv("LOG_LEVEL", "4")
+	t.Setenv("CONTEXT_TIMEOUT", "9")
+
+	env, err := LoadEnv(EnvOptions{
+		ConfigFile: configFile,
+		Overrides:  map[string]string{"CONTEXT_TIMEOUT": "11"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2048, env.AvatarMaxSizeKB, "default")
+	assert.Equal(t, 1024, env.ServerMaxBodyKB, "default")
+	assert.True(t, env.LegacyRoutes, "default")
+	assert.Equal(t, "delete", env.AccountPurgeMode, "default")
+	assert.Equal(t, ":7000", env.ServerAddress, "config file")
+	assert.Equal(t, "dotenv-host", env.DBHost, ".env overrides config file")
+	assert.Equal(t, 4, env.LogLevel, "environment overrides .env")
+	assert.Equal(t, 11, env.ContextTimeout, "flag overrides environment")
+}
+
+func TestLoadEnv_EnvironmentOnly(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("POSTGRES_HOST", "db")
+	t.Setenv("POSTGRES_DB", "app")
+	t.Setenv("POSTGRES_USER", "app")
+	t.Setenv("ACCESS_TOKEN_SECRET", "a")
+	t.Setenv("REFRESH_TOKEN_SECRET", "r")
+	t.Setenv("EMAIL_TOKEN_SECRET", "e")
+	t.Setenv("SIGNUP_CONCEAL_EXISTING", "true")
+
+	env, err := LoadEnv(EnvOptions{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "db", env.DBHost)
+	assert.True(t, env.SignupConcealExisting)
+}
+
+func TestLoadEnv_AggregatesValidationErrors(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("APP_ENV", "qa")
+	t.Setenv("BLOB_STORE", "s3")
+	t.Setenv("TRACING_SAMPLE_RATIO", "2")
+	t.Setenv("LEGACY_ROUTES_SUNSET", "31/03/2027")
+
+	env, err := LoadEnv(EnvOptions{})
+
+	require.NotNil(t, env)
+	var configErr *ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Contains(t, configErr.Problems, `APP_ENV: must be one of [development test staging production], got "qa"`)
+	assert.Contains(t, configErr.Problems, "DB_HOST: is required unless DB_DSN is set")
+	assert.Contains(t, configErr.Problems, "ACCESS_TOKEN_SECRET: is required")
+	assert.Contains(t, configErr.Problems, "S3_BUCKET: is required when BLOB_STORE=s3")
+	assert.Contains(t, configErr.Problems, "TRACING_SAMPLE_RATIO: must be at most 1, got 2")
+	assert.Contains(t, configErr.Problems, `LEGACY_ROUTES_SUNSET: must be a date in 2006-01-02 format, got "31/03/2027"`)
+}
+
+func TestEnv_DatabaseProblems_Replicas(t *testing.T) {
+	env := Env{DBDriver: "sqlite", DBName: "app.db", DBReplicaHosts: "replica-1"}
+	assert.Equal(t, []string{"DB_REPLICA_HOSTS: is not supported when DB_DRIVER=sqlite"}, env.databaseProblems())
+
+	env = Env{DBDriver: "postgres", DBDSN: "postgres://app@db/app", DBReplicaHosts: "replica-1"}
+	assert.Equal(t, []string{"DB_REPLICA_HOSTS: cannot be combined with DB_DSN"}, env.databaseProblems())
+
+	env = Env{DBDriver: "postgres", DBHost: "db", DBName: "app", DBUser: "app", DBReplicaHosts: "replica-1,replica-2:6432"}
+	assert.Empty(t, env.databaseProblems())
+}
+
+func TestEnv_TrustedProxyProblems(t *testing.T) {
+	env := Env{TrustedProxies: "10.0.0.1, 172.16.0.0/12,::1"}
+	assert.Empty(t, env.trustedProxyProblems())
+
+	env.TrustedProxies = "10.0.0.1,proxy.internal"
+	assert.Equal(t, []string{`TRUSTED_PROXIES: "proxy.internal" is not a valid IP or CIDR`}, env.trustedProxyProblems())
+}
+
+func TestEnv_Validate_ProductionSecrets(t *testing.T) {
+	base := Env{
+		AppEnv:                 "production",
+		ServerAddress:          ":8080",
+		AppBaseURL:             "https://api.example.com",
+		ContextTimeout:         2,
+		LogFileMaxSizeMB:       1,
+		LogSamplePeriodSecond:  1,
+		LogSampleThereafter:    1,
+		TracingExporter:        "none",
+		TracingServiceName:     "api",
+		ShutdownTimeoutSecond:  1,
+		ServerMaxBodyKB:        1,
+		DBDriver:               "postgres",
+		DBHost:                 "db",
+		DBPort:                 "5432",
+		DBName:                 "app",
+		DBUser:                 "app",
+		AccessTokenExpiryHour:  1,
+		RefreshTokenExpiryHour: 1,
+		EmailTokenExpiryHour:   1,
+		AccessTokenSecret:      strongSecret,
+		RefreshTokenSecret:     strongSecret + "r",
+		EmailTokenSecret:       strongSecret + "e",
+		AccountPurgeMode:       "delete",
+		BlobStore:              "local",
+		BlobLocalDir:           "./uploads",
+		AvatarMaxSizeKB:        1,
+		AvatarTimeoutSecond:    1,
+	}
+	require.NoError(t, base.Validate())
+
+	sharedMetrics := base
+	sharedMetrics.MetricsAddress = base.ServerAddress
+	var metricsErr *ConfigError
+	require.ErrorAs(t, sharedMetrics.Validate(), &metricsErr)
+	assert.Equal(t, []string{"METRICS_ADDRESS: must differ from SERVER_ADDRESS"}, metricsErr.Problems)
+
+	slowAvatar := base
+	slowAvatar.ServerWriteTimeoutSecond = 5
+	slowAvatar.AvatarTimeoutSecond = 5
+	var avatarErr *ConfigError
+	require.ErrorAs(t, slowAvatar.Validate(), &avatarErr)
+	assert.Equal(t, []string{"AVATAR_TIMEOUT_SECOND: must be less than SERVER_WRITE_TIMEOUT_SECOND"}, avatarErr.Problems)
+
+	weak := base
+	weak.AccessTokenSecret = "access_token_secret"
+	weak.RefreshTokenSecret = "short"
+	weak.EmailTokenSecret = weak.AccessTokenSecret
+
+	var configErr *ConfigError
+	require.ErrorAs(t, weak.Validate(), &configErr)
+	assert.ElementsMatch(t, []string{
+		"ACCESS_TOKEN_SECRET: default or example value is not allowed in production",
+		"EMAIL_TOKEN_SECRET: default or example value is not allowed in production",
+		"EMAIL_TOKEN_SECRET: must differ from ACCESS_TOKEN_SECRET",
+		"REFRESH_TOKEN_SECRET: must be at least 32 bytes in production",
+	}, configErr.Problems)
+
+	development := weak
+	development.AppEnv = "development"
+	assert.NoError(t, development.Validate())
+}
+
+func TestBindEnvFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts := BindEnvFlags(fs)
+
+	require.NoError(t, fs.Parse([]string{"--config", "app.toml", "--server-address", ":9090", "--db-auto-migrate", "serve"}))
+
+	assert.Equal(t, "app.toml", opts.ConfigFile)
+	assert.Equal(t, map[string]string{"SERVER_ADDRESS": ":9090", "DB_AUTO_MIGRATE": "true"}, opts.Overrides)
+	assert.Equal(t, []string{"serve"}, fs.Args())
+}
@@ -0,0 +1,193 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// minProductionSecretLength 为生产环境 JWT 密钥的最小长度（字节），对应 HS256 的 256 位
+const minProductionSecretLength = 32
+
+// knownWeakSecrets 为示例配置和常见占位值，生产环境禁止使用
+var knownWeakSecrets = map[string]bool{
+	"access_token_secret":  true,
+	"refresh_token_secret": true,
+	"email_token_secret":   true,
+	"secret":               true,
+	"changeme":             true,
+	"password":             true,
+}
+
+// ConfigError 汇总配置中的全部问题，便于一次修正
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+var envValidator = newEnvValidator()
+
+func newEnvValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("mapstructure")
+	})
+	return v
+}
+
+// Validate 校验字段规则；生产环境下额外检查 JWT 密钥强度
+func (env *Env) Validate() error {
+	var problems []string
+
+	err := envValidator.Struct(env)
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, fe := range validationErrors {
+			problems = append(problems, describeFieldError(fe))
+		}
+	} else if err != nil {
+		return err
+	}
+
+	problems = append(problems, env.databaseProblems()...)
+	problems = append(problems, env.trustedProxyProblems()...)
+	if env.ServerWriteTimeoutSecond > 0 && env.AvatarTimeoutSecond >= env.ServerWriteTimeoutSecond {
+		problems = append(problems, "AVATAR_TIMEOUT_SECOND: must be less than SERVER_WRITE_TIMEOUT_SECOND")
+	}
+	if env.MetricsAddress != "" && env.MetricsAddress == env.ServerAddress {
+		problems = append(problems, "METRICS_ADDRESS: must differ from SERVER_ADDRESS")
+	}
+	if env.AppEnv == "production" {
+		problems = append(problems, env.weakSecretProblems()...)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return &ConfigError{Problems: problems}
+}
+
+// trustedProxyProblems 检查 TRUSTED_PROXIES 中的每一项都是合法的 IP 或 CIDR
+func (env *Env) trustedProxyProblems() []string {
+	var problems []string
+	for _, proxy := range SplitList(env.TrustedProxies) {
+		if net.ParseIP(proxy) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil {
+			problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES: %q is not a valid IP or CIDR", proxy))
+		}
+	}
+	return problems
+}
+
+// databaseProblems 检查连接参数，设置了 DB_DSN 时不要求单独的连接参数；只读副本复用主库的连接参数，
+// 因此不能与 sqlite 或 DB_DSN 同时使用
+func (env *Env) databaseProblems() []string {
+	var problems []string
+	if env.DBReplicaHosts != "" {
+		if env.DBDriver == "sqlite" {
+			problems = append(problems, "DB_REPLICA_HOSTS: is not supported when DB_DRIVER=sqlite")
+		}
+		if env.DBDSN != "" {
+			problems = append(problems, "DB_REPLICA_HOSTS: cannot be combined with DB_DSN")
+		}
+	}
+	if env.DBDSN != "" {
+		return problems
+	}
+	required := map[string]string{"DB_NAME": env.DBName}
+	if env.DBDriver != "sqlite" {
+		required["DB_HOST"] = env.DBHost
+		required["DB_USER"] = env.DBUser
+	}
+
+	for key, value := range required {
+		if value == "" {
+			problems = append(problems, key+": is required unless DB_DSN is set")
+		}
+	}
+	return problems
+}
+
+func (env *Env) weakSecretProblems() []string {
+	secrets := []struct {
+		key   string
+		value string
+	}{
+		{"ACCESS_TOKEN_SECRET", env.AccessTokenSecret},
+		{"REFRESH_TOKEN_SECRET", env.RefreshTokenSecret},
+		{"EMAIL_TOKEN_SECRET", env.EmailTokenSecret},
+	}
+
+	var problems []string
+	seen := map[string]string{}
+	for _, s := range secrets {
+		if s.value == "" {
+			continue
+		}
+		if err := checkSecretStrength(s.value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", s.key, err))
+		}
+		if other, ok := seen[s.value]; ok {
+			problems = append(problems, fmt.Sprintf("%s: must differ from %s", s.key, other))
+		}
+		seen[s.value] = s.key
+	}
+	return problems
+}
+
+// checkSecretStrength 检查生产环境 JWT 密钥强度，运行时轮换的新密钥同样需要通过检查
+func checkSecretStrength(value string) error {
+	switch {
+	case knownWeakSecrets[strings.ToLower(value)]:
+		return errors.New("default or example value is not allowed in production")
+	case len(value) < minProductionSecretLength:
+		return fmt.Errorf("must be at least %d bytes in production", minProductionSecretLength)
+	}
+	return nil
+}
+
+func describeFieldError(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return fe.Field() + ": is required"
+	case "required_if":
+		return fmt.Sprintf("%s: is required when %s", fe.Field(), describeCondition(fe.Param()))
+	case "oneof":
+		return fmt.Sprintf("%s: must be one of [%s], got %q", fe.Field(), fe.Param(), fmt.Sprint(fe.Value()))
+	case "min":
+		return fmt.Sprintf("%s: must be at least %s, got %v", fe.Field(), fe.Param(), fe.Value())
+	case "max":
+		return fmt.Sprintf("%s: must be at most %s, got %v", fe.Field(), fe.Param(), fe.Value())
+	case "url":
+		return fmt.Sprintf("%s: must be a URL, got %q", fe.Field(), fmt.Sprint(fe.Value()))
+	case "datetime":
+		return fmt.Sprintf("%s: must be a date in %s format, got %q", fe.Field(), fe.Param(), fmt.Sprint(fe.Value()))
+	case "numeric":
+		return fmt.Sprintf("%s: must be numeric, got %q", fe.Field(), fmt.Sprint(fe.Value()))
+	default:
+		return fmt.Sprintf("%s: failed %s validation", fe.Field(), fe.Tag())
+	}
+}
+
+// describeCondition 将 required_if 参数中的结构体字段名转换为配置名，如 "BlobStore s3" -> "BLOB_STORE=s3"
+func describeCondition(param string) string {
+	parts := strings.Fields(param)
+	if len(parts) != 2 {
+		return param
+	}
+	if field, ok := reflect.TypeOf(Env{}).FieldByName(parts[0]); ok {
+		return field.Tag.Get("mapstructure") + "=" + parts[1]
+	}
+	return param
+}
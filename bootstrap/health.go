@@ -0,0 +1,31 @@
+package bootstrap
+
+import (
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/health"
+	zlog "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+// NewHealthRegistry 创建就绪检查注册表，默认包含数据库连通性和迁移版本检查
+func NewHealthRegistry(db *gorm.DB) domain.HealthRegistry {
+	registry := health.NewRegistry(healthCheckTimeout)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("获取数据库连接失败")
+	}
+	registry.Register(health.DBChecker(sqlDB))
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("加载数据库迁移失败")
+	}
+	registry.Register(health.MigrationChecker(migrator))
+
+	return registry
+}
@@ -1,12 +1,44 @@
 package bootstrap
 
 import (
+	"io"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/logging"
+	"github.com/horaoen/go-backend-clean-architecture/internal/tracing"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
-func InitLog(env *Env) {
+// InitLog 按配置替换全局 logger，返回的 io.Closer 用于退出时关闭日志文件
+func InitLog(env *Env) io.Closer {
+	format := env.LogFormat
+	if format == "" {
+		format = logging.FormatJSON
+		if env.AppEnv == "development" {
+			format = logging.FormatConsole
+		}
+	}
+
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	zerolog.SetGlobalLevel(zerolog.Level(env.LogLevel))
-	log.Info().Msgf("logging level: %d", env.LogLevel)
+	logger, closer, err := logging.New(logging.Config{
+		Level:            zerolog.Level(env.LogLevel),
+		Format:           format,
+		File:             env.LogFile,
+		FileMaxSizeMB:    env.LogFileMaxSizeMB,
+		FileMaxAgeDay:    env.LogFileMaxAgeDay,
+		FileMaxBackups:   env.LogFileMaxBackups,
+		SampleBurst:      uint32(max(env.LogSampleBurst, 0)),
+		SamplePeriod:     time.Duration(env.LogSamplePeriodSecond) * time.Second,
+		SampleThereafter: uint32(max(env.LogSampleThereafter, 0)),
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("日志配置无效")
+	}
+
+	log.Logger = logger.Hook(tracing.LogHook{})
+	// 请求之外（如后台 worker、CLI）调用 log.Ctx(ctx) 时回退到全局 logger
+	zerolog.DefaultContextLogger = &log.Logger
+	log.Info().Str("level", zerolog.GlobalLevel().String()).Str("format", format).Msg("logging initialized")
+	return closer
 }
@@ -0,0 +1,27 @@
+package bootstrap
+
+import (
+	"github.com/horaoen/go-backend-clean-architecture/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	zlog "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// NewMetrics 创建指标注册表，并为数据库安装查询耗时插件和连接池统计采集器
+func NewMetrics(db *gorm.DB) *metrics.Metrics {
+	m := metrics.New()
+
+	if err := db.Use(metrics.GormPlugin(m)); err != nil {
+		zlog.Fatal().Err(err).Msg("安装 GORM 指标插件失败")
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("获取数据库连接失败")
+	}
+	if err := m.Register(collectors.NewDBStatsCollector(sqlDB, db.Dialector.Name())); err != nil {
+		zlog.Fatal().Err(err).Msg("注册连接池指标失败")
+	}
+
+	return m
+}
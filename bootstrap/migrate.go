@@ -0,0 +1,31 @@
+package bootstrap
+
+import (
+	"io/fs"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/migrate"
+	"github.com/horaoen/go-backend-clean-architecture/repository/migrations"
+	"gorm.io/gorm"
+)
+
+// NewMigrator 根据当前数据库方言加载 repository/migrations 下对应目录的迁移文件
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	name := db.Dialector.Name()
+
+	dialect, err := migrate.DialectFor(name)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := fs.Sub(migrations.FS, name)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.New(sqlDB, dialect, files)
+}
@@ -0,0 +1,165 @@
+package bootstrap
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+	"github.com/horaoen/go-backend-clean-architecture/internal/ratelimit"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// reloadableKeys 为可热更新的配置项，其余配置修改后需重启才能生效
+var reloadableKeys = map[string]bool{
+	"LOG_LEVEL":                 true,
+	"RATE_LIMIT_RPS":            true,
+	"RATE_LIMIT_BURST":          true,
+	"ACCESS_TOKEN_EXPIRY_HOUR":  true,
+	"REFRESH_TOKEN_EXPIRY_HOUR": true,
+	"EMAIL_TOKEN_EXPIRY_HOUR":   true,
+	"CORS_ALLOWED_ORIGINS":      true,
+	"FEATURE_FLAGS":             true,
+}
+
+// RuntimeSettings 为支持热更新的配置。组件持有对应的 dynconf.Value，每次使用时读取，
+// 或订阅变更事件在配置更新时切换自身状态
+type RuntimeSettings struct {
+	LogLevel       *dynconf.Value[zerolog.Level]
+	RateLimit      *dynconf.Value[ratelimit.Config]
+	TokenLifetimes *dynconf.Value[usecase.TokenLifetimes]
+	CORSOrigins    *dynconf.Value[[]string]
+	FeatureFlags   *dynconf.Value[domain.FeatureFlags]
+}
+
+func NewRuntimeSettings(env *Env) *RuntimeSettings {
+	s := &RuntimeSettings{
+		LogLevel:       dynconf.NewValue(zerolog.Level(env.LogLevel)),
+		RateLimit:      dynconf.NewValue(rateLimitConfig(env)),
+		TokenLifetimes: dynconf.NewValue(tokenLifetimes(env)),
+		CORSOrigins:    dynconf.NewValue(SplitList(env.CORSAllowedOrigins)),
+		FeatureFlags:   dynconf.NewValue(featureFlags(env)),
+	}
+	// 日志级别也可通过管理接口调整，这里只在配置文件中的值变化时覆盖
+	s.LogLevel.Subscribe(func(c dynconf.Change[zerolog.Level]) {
+		zerolog.SetGlobalLevel(c.New)
+	})
+	logChanges("LOG_LEVEL", s.LogLevel)
+	logChanges("RATE_LIMIT", s.RateLimit)
+	logChanges("TOKEN_LIFETIMES", s.TokenLifetimes)
+	logChanges("CORS_ALLOWED_ORIGINS", s.CORSOrigins)
+	logChanges("FEATURE_FLAGS", s.FeatureFlags)
+	return s
+}
+
+// Apply 将 env 中可热更新的配置写入各个 Value，值未变化的配置不会触发事件
+func (s *RuntimeSettings) Apply(env *Env) {
+	s.LogLevel.Store(zerolog.Level(env.LogLevel))
+	s.RateLimit.Store(rateLimitConfig(env))
+	s.TokenLifetimes.Store(tokenLifetimes(env))
+	s.CORSOrigins.Store(SplitList(env.CORSAllowedOrigins))
+	s.FeatureFlags.Store(featureFlags(env))
+}
+
+func rateLimitConfig(env *Env) ratelimit.Config {
+	return ratelimit.Config{RPS: env.RateLimitRPS, Burst: env.RateLimitBurst}
+}
+
+func tokenLifetimes(env *Env) usecase.TokenLifetimes {
+	return usecase.TokenLifetimes{
+		AccessTokenExpiryHour:  env.AccessTokenExpiryHour,
+		RefreshTokenExpiryHour: env.RefreshTokenExpiryHour,
+		EmailTokenExpiryHour:   env.EmailTokenExpiryHour,
+	}
+}
+
+func featureFlags(env *Env) domain.FeatureFlags {
+	flags := domain.FeatureFlags{}
+	for _, name := range SplitList(env.FeatureFlags) {
+		flags[name] = true
+	}
+	return flags
+}
+
+func logChanges[T any](name string, v *dynconf.Value[T]) {
+	v.Subscribe(func(c dynconf.Change[T]) {
+		log.Info().Str("setting", name).
+			Str("old", fmt.Sprint(c.Old)).Str("new", fmt.Sprint(c.New)).
+			Msg("配置已热更新")
+	})
+}
+
+// ConfigReloader 在配置文件变化时重新加载全部配置来源。未通过校验的更新被整体拒绝，
+// 通过校验后只应用 reloadableKeys 中的配置
+type ConfigReloader struct {
+	opts     EnvOptions
+	baseline *Env
+	settings *RuntimeSettings
+	mu       sync.Mutex
+}
+
+// NewConfigReloader 创建 reloader，baseline 为启动时加载的配置，用于提示哪些修改需要重启
+func NewConfigReloader(opts EnvOptions, baseline *Env, settings *RuntimeSettings) *ConfigReloader {
+	return &ConfigReloader{opts: opts, baseline: baseline, settings: settings}
+}
+
+// Reload 重新加载并校验配置，失败时保持当前配置并返回错误
+func (r *ConfigReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	env, err := LoadEnv(r.opts)
+	if err != nil {
+		log.Error().Err(err).Msg("配置热更新被拒绝")
+		return err
+	}
+	if keys := r.restartRequiredKeys(env); len(keys) > 0 {
+		log.Warn().Strs("keys", keys).Msg("以下配置修改需重启后生效")
+	}
+	r.settings.Apply(env)
+	return nil
+}
+
+// Watch 使用 viper 监听配置文件，文件变化时调用 Reload。未使用配置文件时返回 false。
+// 监听持续到进程退出；.env 和环境变量不会被监听
+func (r *ConfigReloader) Watch() bool {
+	v := viper.New()
+	if err := readConfigFile(v, r.opts.ConfigFile); err != nil || v.ConfigFileUsed() == "" {
+		return false
+	}
+	v.OnConfigChange(func(e fsnotify.Event) {
+		log.Info().Str("file", e.Name).Msg("检测到配置文件变化")
+		_ = r.Reload()
+	})
+	v.WatchConfig()
+	return true
+}
+
+func (r *ConfigReloader) restartRequiredKeys(env *Env) []string {
+	before, after := envFieldsByKey(r.baseline), envFieldsByKey(env)
+	var keys []string
+	for key, value := range after {
+		if reloadableKeys[key] {
+			continue
+		}
+		// 由 SECRET_PROVIDER 定期刷新的 JWT 密钥无需重启
+		if env.SecretProvider != "" && env.SecretProvider != "none" && isJWTSecretKey(key) {
+			continue
+		}
+		if !reflect.DeepEqual(before[key].Interface(), value.Interface()) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isJWTSecretKey(key string) bool {
+	return key == "ACCESS_TOKEN_SECRET" || key == "REFRESH_TOKEN_SECRET" || key == "EMAIL_TOKEN_SECRET"
+}
@@ -0,0 +1,103 @@
+package bootstrap
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+	"github.com/horaoen/go-backend-clean-architecture/internal/ratelimit"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reloadBaseConfig = `
+app_env: development
+db_host: db
+db_name: app
+db_user: app
+access_token_secret: a
+refresh_token_secret: r
+email_token_secret: e
+`
+
+func newTestReloader(t *testing.T, config string) (*ConfigReloader, *RuntimeSettings, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Cleanup(func() { zerolog.SetGlobalLevel(zerolog.TraceLevel) })
+
+	file := writeFile(t, dir, "config.yaml", reloadBaseConfig+config)
+	opts := EnvOptions{ConfigFile: file}
+	env, err := LoadEnv(opts)
+	require.NoError(t, err)
+
+	settings := NewRuntimeSettings(env)
+	return NewConfigReloader(opts, env, settings), settings, file
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	reloader, settings, file := newTestReloader(t, "log_level: 1\nrate_limit_rps: 5\n")
+	var rateChanges []dynconf.Change[ratelimit.Config]
+	settings.RateLimit.Subscribe(func(c dynconf.Change[ratelimit.Config]) { rateChanges = append(rateChanges, c) })
+
+	writeFile(t, "", file, reloadBaseConfig+`
+log_level: 3
+rate_limit_rps: 10
+rate_limit_burst: 2
+access_token_expiry_hour: 1
+cors_allowed_origins: "https://a.example.com, https://b.example.com"
+feature_flags: beta
+server_address: ":9999"
+`)
+	require.NoError(t, reloader.Reload())
+
+	assert.Equal(t, zerolog.ErrorLevel, settings.LogLevel.Load())
+	assert.Equal(t, zerolog.ErrorLevel, zerolog.GlobalLevel())
+	assert.Equal(t, ratelimit.Config{RPS: 10, Burst: 2}, settings.RateLimit.Load())
+	assert.Equal(t, 1, settings.TokenLifetimes.Load().AccessTokenExpiryHour)
+	assert.Equal(t, 168, settings.TokenLifetimes.Load().RefreshTokenExpiryHour)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, settings.CORSOrigins.Load())
+	assert.Equal(t, domain.FeatureFlags{"beta": true}, settings.FeatureFlags.Load())
+	require.Len(t, rateChanges, 1)
+	assert.Equal(t, ratelimit.Config{RPS: 5, Burst: 20}, rateChanges[0].Old)
+
+	assert.Equal(t, []string{"SERVER_ADDRESS"}, reloader.restartRequiredKeys(mustLoadEnv(t, reloader.opts)))
+}
+
+func TestConfigReloader_RejectsInvalidUpdate(t *testing.T) {
+	reloader, settings, file := newTestReloader(t, "rate_limit_rps: 5\n")
+
+	writeFile(t, "", file, reloadBaseConfig+"rate_limit_rps: -1\naccess_token_expiry_hour: 0\n")
+	var configErr *ConfigError
+	require.ErrorAs(t, reloader.Reload(), &configErr)
+
+	assert.Equal(t, 5.0, settings.RateLimit.Load().RPS, "invalid update is not applied")
+	assert.Equal(t, 2, settings.TokenLifetimes.Load().AccessTokenExpiryHour)
+}
+
+func TestConfigReloader_Watch(t *testing.T) {
+	reloader, settings, file := newTestReloader(t, "feature_flags: a\n")
+	require.True(t, reloader.Watch())
+
+	require.NoError(t, os.WriteFile(file, []byte(reloadBaseConfig+"feature_flags: a,b\n"), 0o600))
+
+	assert.Eventually(t, func() bool {
+		return settings.FeatureFlags.Load().Enabled("b")
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestConfigReloader_WatchWithoutConfigFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	reloader := NewConfigReloader(EnvOptions{}, &Env{}, NewRuntimeSettings(&Env{}))
+	assert.False(t, reloader.Watch())
+}
+
+func mustLoadEnv(t *testing.T, opts EnvOptions) *Env {
+	t.Helper()
+	env, err := LoadEnv(opts)
+	require.NoError(t, err)
+	return env
+}
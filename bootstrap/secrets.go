@@ -0,0 +1,150 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/secrets"
+	"github.com/spf13/viper"
+)
+
+// secretFetchTimeout 为启动时从 provider 读取密钥的最长等待时间
+const secretFetchTimeout = 10 * time.Second
+
+// providedSecretKeys 为可由 SECRET_PROVIDER 提供的配置项，provider 中的密钥名与配置名相同
+var providedSecretKeys = []string{
+	"DB_PASSWORD",
+	"ACCESS_TOKEN_SECRET",
+	"REFRESH_TOKEN_SECRET",
+	"EMAIL_TOKEN_SECRET",
+	"S3_SECRET_KEY",
+}
+
+// JWTSecrets 为可在运行时轮换的 JWT 密钥
+type JWTSecrets struct {
+	Access  *secrets.Value
+	Refresh *secrets.Value
+	Email   *secrets.Value
+}
+
+func NewJWTSecrets(env *Env) JWTSecrets {
+	return JWTSecrets{
+		Access:  secrets.Static(env.AccessTokenSecret),
+		Refresh: secrets.Static(env.RefreshTokenSecret),
+		Email:   secrets.Static(env.EmailTokenSecret),
+	}
+}
+
+// NewSecretProvider 按 SECRET_PROVIDER 创建密钥来源，none 时返回 nil
+func NewSecretProvider(env *Env) (domain.SecretProvider, error) {
+	switch env.SecretProvider {
+	case "", "none":
+		return nil, nil
+	case "file":
+		return secrets.NewFileProvider(env.SecretDir), nil
+	case "vault":
+		return secrets.NewVaultProvider(secrets.VaultConfig{
+			Address: env.VaultAddr,
+			Token:   env.VaultToken,
+			Mount:   env.VaultKVMount,
+			Path:    env.VaultSecretPath,
+		}, nil)
+	default:
+		return nil, fmt.Errorf("unknown secret provider: %s", env.SecretProvider)
+	}
+}
+
+// NewSecretRefresher 创建定期刷新 JWT 密钥的刷新器，未配置 provider 时返回 nil。
+// 生产环境下未通过强度检查的新密钥会被拒绝，继续使用原密钥
+func NewSecretRefresher(env *Env, jwtSecrets JWTSecrets) (*secrets.Refresher, error) {
+	provider, err := NewSecretProvider(env)
+	if err != nil || provider == nil {
+		return nil, err
+	}
+
+	var validate func(name, value string) error
+	if env.AppEnv == "production" {
+		validate = func(_, value string) error { return checkSecretStrength(value) }
+	}
+	refresher := secrets.NewRefresher(provider, validate)
+	refresher.Bind("ACCESS_TOKEN_SECRET", jwtSecrets.Access)
+	refresher.Bind("REFRESH_TOKEN_SECRET", jwtSecrets.Refresh)
+	refresher.Bind("EMAIL_TOKEN_SECRET", jwtSecrets.Email)
+	return refresher, nil
+}
+
+// applySecretFiles 处理 Docker/Kubernetes 风格的 *_FILE 配置：KEY_FILE 指向的文件内容作为 KEY 的值。
+// 为避免歧义，KEY 与 KEY_FILE 不能同时通过环境变量设置
+func applySecretFiles(v *viper.Viper) error {
+	for _, key := range envKeys() {
+		if !isSecretKey(key) {
+			continue
+		}
+		fileKey := key + "_FILE"
+		input := []string{fileKey, fileKey}
+		if legacy, ok := legacyEnvKeys[key]; ok {
+			v.RegisterAlias(legacy+"_FILE", fileKey)
+			input = append(input, legacy+"_FILE")
+		}
+		if err := v.BindEnv(input...); err != nil {
+			return err
+		}
+		path := v.GetString(fileKey)
+		if path == "" {
+			continue
+		}
+		if _, ok := os.LookupEnv(key); ok {
+			if _, ok := os.LookupEnv(fileKey); ok {
+				return fmt.Errorf("both %s and %s are set", key, fileKey)
+			}
+		}
+		value, err := secrets.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", fileKey, err)
+		}
+		v.Set(key, value)
+	}
+	return nil
+}
+
+// resolveSecrets 用 provider 中的值覆盖 providedSecretKeys 对应的配置，provider 中不存在的密钥保持原值
+func (env *Env) resolveSecrets(ctx context.Context) error {
+	provider, err := NewSecretProvider(env)
+	if err != nil || provider == nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, secretFetchTimeout)
+	defer cancel()
+
+	fields := envFieldsByKey(env)
+	for _, key := range providedSecretKeys {
+		value, err := provider.GetSecret(ctx, key)
+		if errors.Is(err, domain.ErrSecretNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("load secret %s from %s: %w", key, env.SecretProvider, err)
+		}
+		fields[key].SetString(value)
+	}
+	return nil
+}
+
+// envFieldsByKey 返回配置名到 env 字段的映射
+func envFieldsByKey(env *Env) map[string]reflect.Value {
+	v := reflect.ValueOf(env).Elem()
+	t := v.Type()
+	fields := make(map[string]reflect.Value, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if key := t.Field(i).Tag.Get("mapstructure"); key != "" {
+			fields[key] = v.Field(i)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,111 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/secrets"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("APP_ENV", "development")
+	t.Setenv("DB_HOST", "db")
+	t.Setenv("DB_NAME", "app")
+	t.Setenv("DB_USER", "app")
+	t.Setenv("ACCESS_TOKEN_SECRET", "a")
+	t.Setenv("REFRESH_TOKEN_SECRET", "r")
+	t.Setenv("EMAIL_TOKEN_SECRET", "e")
+}
+
+func TestLoadEnv_SecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	setRequiredEnv(t)
+	t.Setenv("POSTGRES_PASSWORD_FILE", writeFile(t, dir, "pg", "from-file\n"))
+	writeFile(t, dir, ".env", "S3_SECRET_KEY_FILE="+writeFile(t, dir, "s3", "s3-from-file"))
+
+	env, err := LoadEnv(EnvOptions{
+		Overrides: map[string]string{"S3_SECRET_KEY": "flag"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", env.DBPassword)
+	assert.Equal(t, "flag", env.S3SecretKey, "flag overrides *_FILE")
+}
+
+func TestLoadEnv_SecretFileConflict(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	setRequiredEnv(t)
+	t.Setenv("ACCESS_TOKEN_SECRET_FILE", writeFile(t, dir, "access", "x"))
+
+	_, err := LoadEnv(EnvOptions{})
+
+	assert.ErrorContains(t, err, "both ACCESS_TOKEN_SECRET and ACCESS_TOKEN_SECRET_FILE are set")
+}
+
+func TestLoadEnv_SecretFileMissing(t *testing.T) {
+	t.Chdir(t.TempDir())
+	setRequiredEnv(t)
+	t.Setenv("DB_PASSWORD_FILE", "/nonexistent/pg")
+
+	_, err := LoadEnv(EnvOptions{})
+
+	assert.ErrorContains(t, err, "read DB_PASSWORD_FILE")
+}
+
+func TestLoadEnv_FileSecretProvider(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	secretDir := t.TempDir()
+	writeFile(t, secretDir, "ACCESS_TOKEN_SECRET", strongSecret+"\n")
+	setRequiredEnv(t)
+	t.Setenv("SECRET_PROVIDER", "file")
+	t.Setenv("SECRET_DIR", secretDir)
+
+	env, err := LoadEnv(EnvOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, strongSecret, env.AccessTokenSecret, "provider overrides environment")
+	assert.Equal(t, "r", env.RefreshTokenSecret, "missing secret keeps configured value")
+
+	jwtSecrets := NewJWTSecrets(env)
+	refresher, err := NewSecretRefresher(env, jwtSecrets)
+	require.NoError(t, err)
+
+	writeFile(t, secretDir, "ACCESS_TOKEN_SECRET", "rotated")
+	writeFile(t, secretDir, "REFRESH_TOKEN_SECRET", "rotated-refresh")
+	writeFile(t, secretDir, "EMAIL_TOKEN_SECRET", "e")
+	require.NoError(t, refresher.Refresh(t.Context()))
+
+	assert.Equal(t, []string{"rotated", strongSecret}, jwtSecrets.Access.Candidates())
+	assert.Equal(t, "rotated-refresh", jwtSecrets.Refresh.Current())
+	assert.Equal(t, []string{"e"}, jwtSecrets.Email.Candidates())
+}
+
+func TestNewSecretRefresher_ProductionRejectsWeakSecret(t *testing.T) {
+	secretDir := t.TempDir()
+	env := &Env{AppEnv: "production", SecretProvider: "file", SecretDir: secretDir}
+	jwtSecrets := JWTSecrets{
+		Access:  secrets.Static(strongSecret),
+		Refresh: secrets.Static(strongSecret + "r"),
+		Email:   secrets.Static(strongSecret + "e"),
+	}
+	refresher, err := NewSecretRefresher(env, jwtSecrets)
+	require.NoError(t, err)
+
+	writeFile(t, secretDir, "ACCESS_TOKEN_SECRET", "short")
+	writeFile(t, secretDir, "REFRESH_TOKEN_SECRET", strongSecret+"r")
+	writeFile(t, secretDir, "EMAIL_TOKEN_SECRET", strongSecret+"e")
+
+	assert.ErrorContains(t, refresher.Refresh(t.Context()), "must be at least 32 bytes")
+	assert.Equal(t, strongSecret, jwtSecrets.Access.Current())
+}
+
+func TestNewSecretRefresher_NoProvider(t *testing.T) {
+	refresher, err := NewSecretRefresher(&Env{SecretProvider: "none"}, JWTSecrets{})
+	require.NoError(t, err)
+	assert.Nil(t, refresher)
+}
@@ -0,0 +1,29 @@
+package bootstrap
+
+import (
+	"context"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/tracing"
+	zlog "github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// NewTracing 初始化全局 TracerProvider 并为数据库安装查询 span 插件，返回退出时刷新 span 的函数
+func NewTracing(env *Env, db *gorm.DB) func(context.Context) error {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     env.TracingExporter,
+		ServiceName:  env.TracingServiceName,
+		SampleRatio:  env.TracingSampleRatio,
+		OTLPEndpoint: env.TracingOTLPEndpoint,
+		OTLPInsecure: env.TracingOTLPInsecure,
+	})
+	if err != nil {
+		zlog.Fatal().Err(err).Msg("初始化链路追踪失败")
+	}
+
+	if err := db.Use(tracing.GormPlugin()); err != nil {
+		zlog.Fatal().Err(err).Msg("安装 GORM 追踪插件失败")
+	}
+
+	return shutdown
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
+)
+
+// runConfig 打印合并各来源后的生效配置；配置校验失败时仍打印，并返回汇总的错误
+func runConfig(opts bootstrap.EnvOptions, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errUsage
+	}
+
+	env, err := bootstrap.LoadEnv(opts)
+	if env == nil {
+		return err
+	}
+	for _, line := range env.RedactedLines() {
+		fmt.Println(line)
+	}
+	return err
+}
@@ -1,17 +1,42 @@
 package main
 
 import (
-	"time"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	_ "time/tzdata"
 
-	"github.com/gin-gonic/gin"
-	"github.com/horaoen/go-backend-clean-architecture/api/route"
 	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
 	"github.com/rs/zerolog/log"
 )
 
+const usage = `usage: [global flags] <command> [arguments]
+
+global flags:
+  --config <file>                         YAML/TOML/JSON config file
+  --env-file <file>                       .env file (default .env, optional)
+  --<key> <value>                         override any config key, e.g. --server-address :9090
+
+configuration precedence: defaults < config file < .env < environment variables < flags
+
+commands:
+  serve                                   start the HTTP server (default)
+  migrate up | down [steps] | status      manage database schema migrations
+  user create | disable | set-role | reset-password
+                                          manage user accounts
+  token issue --email <email>             issue a token pair for debugging
+  config print                            print effective configuration with secrets redacted
+  seed                                    create demo accounts (non-production only)`
+
+var errUsage = errors.New(usage)
+
 // @title           Go Backend Clean Architecture API
 // @version         1.0
 // @description     This is a sample server for Go Backend Clean Architecture.
+// @description     Error messages (problem+json detail and field messages) are localized by the Accept-Language header; en and zh are supported.
+// @description     Request bodies may be JSON (unknown fields are rejected) or form encoded and are limited to SERVER_MAX_BODY_KB. Responses default to JSON; send Accept: application/x-msgpack or application/x-protobuf (a google.protobuf.Struct) for binary encodings.
+// @description     Routes without the /api/v1 prefix are deprecated and answer with Deprecation (and, once scheduled, Sunset) headers.
 // @termsOfService  http://swagger.io/terms/
 
 // @contact.name    API Support
@@ -22,28 +47,64 @@ import (
 // @license.url     http://www.apache.org/licenses/LICENSE-2.0.html
 
 // @host            localhost:8080
-// @BasePath        /
+// @BasePath        /api/v1
 // @schemes         http
 
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
 func main() {
+	global := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	global.Usage = func() { fmt.Fprintln(os.Stderr, usage) }
+	envOpts := bootstrap.BindEnvFlags(global)
+	if err := global.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
 
-	app := bootstrap.App()
-
-	env := app.Env
-
-	defer app.CloseDBConnection()
+	command, args := "serve", global.Args()
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
 
-	timeout := time.Duration(env.ContextTimeout) * time.Second
+	if err := run(*envOpts, command, args); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		log.Err(err).Str("command", command).Msg("命令执行失败")
+		os.Exit(1)
+	}
+}
 
-	engine := gin.Default()
+func run(envOpts bootstrap.EnvOptions, command string, args []string) error {
+	switch command {
+	case "config":
+		// config 只读取配置，不需要连接数据库
+		return runConfig(envOpts, args)
+	case "serve", "migrate", "user", "token", "seed":
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	default:
+		return errUsage
+	}
 
-	route.Setup(env, timeout, app.DB, engine)
+	app := bootstrap.App(envOpts)
+	defer app.Close()
 
-	err := engine.Run(env.ServerAddress)
-	if err != nil {
-		log.Err(err).Msg("应用启动失败")
+	switch command {
+	case "serve":
+		return runServe(&app, args)
+	case "migrate":
+		return runMigrate(&app, args)
+	case "user":
+		return runUser(&app, args)
+	case "token":
+		return runToken(&app, args)
+	default:
+		return runSeed(&app, args)
 	}
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
+	"github.com/rs/zerolog/log"
+)
+
+func runMigrate(app *bootstrap.Application, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	migrator, err := bootstrap.NewMigrator(app.DB)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Info().Int64("version", m.Version).Str("name", m.Name).Msg("迁移已执行")
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Info().Msg("数据库已是最新版本")
+		}
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps: %s", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			log.Info().Int64("version", m.Version).Str("name", m.Name).Msg("迁移已回滚")
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s  %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	default:
+		return errUsage
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/repository"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+	"github.com/rs/zerolog/log"
+)
+
+type seedUser struct {
+	name  string
+	email string
+	role  string
+}
+
+var seedUsers = []seedUser{
+	{name: "Admin", email: "admin@example.com", role: domain.RoleAdmin},
+	{name: "Demo User", email: "user@example.com", role: domain.RoleUser},
+}
+
+// runSeed 创建演示账号，已存在的账号会被跳过，可重复执行
+func runSeed(app *bootstrap.Application, args []string) error {
+	fs := newFlagSet("seed")
+	password := fs.String("password", "password123", "password for seeded accounts")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if app.Env.AppEnv == "production" {
+		return errors.New("seed is not allowed in production")
+	}
+
+	userAdmin := usecase.NewUserAdminUsecase(
+		repository.NewUserRepository(app.DB),
+		repository.NewTxManager(app.DB),
+		time.Duration(app.Env.ContextTimeout)*time.Second,
+	)
+	ctx := context.Background()
+
+	for _, su := range seedUsers {
+		user, err := userAdmin.CreateUser(ctx, su.name, su.email, *password, su.role)
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			log.Info().Str("email", su.email).Msg("演示账号已存在，跳过")
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("seed %s: %w", su.email, err)
+		}
+		log.Info().Uint("id", user.ID).Str("email", user.Email).Str("role", user.Role).Msg("已创建演示账号")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/horaoen/go-backend-clean-architecture/api/route"
+	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
+	"github.com/horaoen/go-backend-clean-architecture/internal/health"
+	"github.com/horaoen/go-backend-clean-architecture/internal/server"
+	"github.com/horaoen/go-backend-clean-architecture/internal/worker"
+	"github.com/horaoen/go-backend-clean-architecture/repository"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+	"github.com/rs/zerolog/log"
+)
+
+var errShuttingDown = errors.New("server is shutting down")
+
+// runServe 启动 HTTP 服务和后台 worker。收到 SIGINT/SIGTERM 后按顺序关闭：
+// 就绪探针失败并摘流 -> 等待处理中的请求 -> 停止 worker -> 刷新 span 并关闭数据库（由调用方 defer 完成）
+func runServe(app *bootstrap.Application, args []string) error {
+	if len(args) > 0 {
+		return errUsage
+	}
+
+	env := app.Env
+	timeout := time.Duration(env.ContextTimeout) * time.Second
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		// 第一次信号触发优雅关闭，之后恢复默认行为，再次发送信号可强制退出
+		<-ctx.Done()
+		stop()
+	}()
+
+	workers := worker.NewGroup(context.Background())
+	defer workers.Stop()
+	startAccountPurgeWorker(workers, app, timeout)
+	startSecretRefreshWorker(workers, app)
+	app.WatchConfig()
+
+	if env.AppEnv != "development" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+	engine := gin.New()
+	// 限流等按 ClientIP 区分客户端，只信任配置的代理转发的 X-Forwarded-For，防止伪造
+	if err := engine.SetTrustedProxies(bootstrap.SplitList(env.TrustedProxies)); err != nil {
+		return err
+	}
+
+	config := server.Config{
+		Addr:            env.ServerAddress,
+		ReadTimeout:     time.Duration(env.ServerReadTimeoutSecond) * time.Second,
+		WriteTimeout:    time.Duration(env.ServerWriteTimeoutSecond) * time.Second,
+		IdleTimeout:     time.Duration(env.ServerIdleTimeoutSecond) * time.Second,
+		DrainPeriod:     time.Duration(env.ShutdownDrainSecond) * time.Second,
+		ShutdownTimeout: time.Duration(env.ShutdownTimeoutSecond) * time.Second,
+	}
+	srv := server.New(config, engine)
+	app.Health.Register(health.CheckerFunc("server", func(context.Context) error {
+		if !srv.Ready() {
+			return errShuttingDown
+		}
+		return nil
+	}))
+
+	route.Setup(app, timeout, engine)
+
+	servers := []*server.Server{srv}
+	if env.MetricsAddress != "" {
+		// 指标接口只在内部地址提供，与业务服务同时摘流和关闭，便于抓取关闭期间的指标
+		config.Addr = env.MetricsAddress
+		servers = append(servers, server.New(config, route.NewMetricsHandler(app.Metrics)))
+	}
+	return serveAll(ctx, servers...)
+}
+
+// serveAll 并行运行 servers，任一服务异常退出时关闭其余服务，返回第一个错误
+func serveAll(ctx context.Context, servers ...*server.Server) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			errs <- srv.ListenAndServe(ctx)
+		}()
+	}
+	var first error
+	for range servers {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+			cancel()
+		}
+	}
+	return first
+}
+
+func startAccountPurgeWorker(workers *worker.Group, app *bootstrap.Application, timeout time.Duration) {
+	env := app.Env
+	if env.AccountPurgeIntervalMinute <= 0 {
+		return
+	}
+
+	purgeUsecase := usecase.NewAccountPurgeUsecase(
+		repository.NewUserRepository(app.DB),
+		app.BlobStore,
+		time.Duration(env.AccountPurgeAfterHour)*time.Hour,
+		env.AccountPurgeMode,
+		timeout,
+	)
+	interval := time.Duration(env.AccountPurgeIntervalMinute) * time.Minute
+
+	workers.Go(func(ctx context.Context) {
+		worker.RunPeriodic(ctx, "account-purge", interval, func(ctx context.Context) error {
+			purged, err := purgeUsecase.PurgeDeleted(ctx)
+			if purged > 0 {
+				log.Info().Int64("purged", purged).Msg("已清理过期删除账号")
+			}
+			return err
+		})
+	})
+}
+
+func startSecretRefreshWorker(workers *worker.Group, app *bootstrap.Application) {
+	if app.SecretRefresher == nil || app.Env.SecretRefreshIntervalSecond <= 0 {
+		return
+	}
+	interval := time.Duration(app.Env.SecretRefreshIntervalSecond) * time.Second
+
+	workers.Go(func(ctx context.Context) {
+		worker.RunPeriodic(ctx, "secret-refresh", interval, app.SecretRefresher.Refresh)
+	})
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/repository"
+	"github.com/rs/zerolog/log"
+)
+
+func runToken(app *bootstrap.Application, args []string) error {
+	if len(args) == 0 || args[0] != "issue" {
+		return errUsage
+	}
+
+	fs := newFlagSet("token issue")
+	email := fs.String("email", "", "login email")
+	if err := parseFlags(fs, args[1:], "email"); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(app.Env.ContextTimeout)*time.Second)
+	defer cancel()
+
+	user, err := repository.NewUserRepository(app.DB).GetByEmail(ctx, *email)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+	tokens, err := app.TokenService.GenerateTokenPair(&user)
+	if err != nil {
+		return err
+	}
+	log.Warn().Str("email", user.Email).Msg("已通过命令行签发 token，仅用于调试")
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]string{
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+	})
+}
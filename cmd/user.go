@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/bootstrap"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/repository"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+)
+
+func runUser(app *bootstrap.Application, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	userAdmin := usecase.NewUserAdminUsecase(
+		repository.NewUserRepository(app.DB),
+		repository.NewTxManager(app.DB),
+		time.Duration(app.Env.ContextTimeout)*time.Second,
+	)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		fs := newFlagSet("user create")
+		name := fs.String("name", "", "display name")
+		email := fs.String("email", "", "login email")
+		password := fs.String("password", "", "password, read from stdin if omitted")
+		role := fs.String("role", domain.RoleUser, "user or admin")
+		if err := parseFlags(fs, args[1:], "name", "email"); err != nil {
+			return err
+		}
+		pwd, err := readPassword(*password)
+		if err != nil {
+			return err
+		}
+		user, err := userAdmin.CreateUser(ctx, *name, *email, pwd, *role)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created user %d <%s> role=%s\n", user.ID, user.Email, user.Role)
+		return nil
+	case "disable":
+		fs := newFlagSet("user disable")
+		email := fs.String("email", "", "login email")
+		if err := parseFlags(fs, args[1:], "email"); err != nil {
+			return err
+		}
+		if err := userAdmin.DisableUser(ctx, *email); err != nil {
+			return err
+		}
+		fmt.Printf("disabled user <%s>\n", *email)
+		return nil
+	case "set-role":
+		fs := newFlagSet("user set-role")
+		email := fs.String("email", "", "login email")
+		role := fs.String("role", "", "user or admin")
+		if err := parseFlags(fs, args[1:], "email", "role"); err != nil {
+			return err
+		}
+		if err := userAdmin.SetRole(ctx, *email, *role); err != nil {
+			return err
+		}
+		fmt.Printf("set role of <%s> to %s\n", *email, *role)
+		return nil
+	case "reset-password":
+		fs := newFlagSet("user reset-password")
+		email := fs.String("email", "", "login email")
+		password := fs.String("password", "", "new password, read from stdin if omitted")
+		if err := parseFlags(fs, args[1:], "email"); err != nil {
+			return err
+		}
+		pwd, err := readPassword(*password)
+		if err != nil {
+			return err
+		}
+		if err := userAdmin.ResetPassword(ctx, *email, pwd); err != nil {
+			return err
+		}
+		fmt.Printf("reset password of <%s>\n", *email)
+		return nil
+	default:
+		return errUsage
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "context"
+
+// 保留期结束后账号的清理方式
+const (
+	// PurgeModeDelete 物理删除账号记录
+	PurgeModeDelete = "delete"
+	// PurgeModeAnonymize 保留记录但抹除其中的个人数据
+	PurgeModeAnonymize = "anonymize"
+)
+
+type AccountPurgeUsecase interface {
+	// PurgeDeleted 删除或匿名化已超过保留期的软删除账号及其头像文件，返回处理的账号数量；
+	// 头像删除失败的账号被跳过并汇总在返回的 error 中，不影响其余账号
+	PurgeDeleted(c context.Context) (int64, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// AccountStatusUsecase 校验 access token 对应的账号当前是否仍可使用，
+// 使停用、删除或被降级的账号无需等待 token 过期即失去相应权限
+type AccountStatusUsecase interface {
+	// CheckActive 返回账号当前的角色；账号已停用时返回 ErrUserDisabled，已删除或不存在时返回 ErrUnauthorized
+	CheckActive(c context.Context, userID string) (role string, err error)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore 抽象文件存储，key 使用 "/" 分隔的相对路径
+type BlobStore interface {
+	Put(c context.Context, key string, data io.Reader, size int64, contentType string) error
+	Delete(c context.Context, key string) error
+	// URL 返回 key 对应的公开访问地址
+	URL(key string) string
+}
@@ -0,0 +1,113 @@
+// Package domain
+package domain
+
+import "net/http"
+
+// ErrorCode 为稳定的机器可读错误码，客户端应依据它而不是错误信息做判断；已发布的取值不得修改
+type ErrorCode string
+
+const (
+	CodeInvalidRequest           ErrorCode = "invalid_request"
+	CodeValidationFailed         ErrorCode = "validation_failed"
+	CodeUnauthorized             ErrorCode = "unauthorized"
+	CodeInvalidCredentials       ErrorCode = "invalid_credentials"
+	CodeInvalidToken             ErrorCode = "invalid_token"
+	CodeForbidden                ErrorCode = "forbidden"
+	CodeUserDisabled             ErrorCode = "user_disabled"
+	CodeNotFound                 ErrorCode = "not_found"
+	CodeUserNotFound             ErrorCode = "user_not_found"
+	CodeUserAlreadyExists        ErrorCode = "user_already_exists"
+	CodePayloadTooLarge          ErrorCode = "payload_too_large"
+	CodeUnsupportedMediaType     ErrorCode = "unsupported_media_type"
+	CodeNotAcceptable            ErrorCode = "not_acceptable"
+	CodeInvalidImage             ErrorCode = "invalid_image"
+	CodeInvalidProfileAttributes ErrorCode = "invalid_profile_attributes"
+	CodeInvalidRole              ErrorCode = "invalid_role"
+	CodeInvalidLogLevel          ErrorCode = "invalid_log_level"
+	CodeRateLimited              ErrorCode = "rate_limited"
+	CodeTimeout                  ErrorCode = "timeout"
+	CodeInternal                 ErrorCode = "internal_error"
+)
+
+// FieldError 描述单个请求字段的校验失败，Rule 为失败的规则名（如 required、email、max）
+type FieldError struct {
+	Field   string
+	Rule    string
+	Param   string
+	Message string
+}
+
+// Error 为带错误码、HTTP 状态码和字段详情的业务错误。
+// errors.Is 按错误码比较，因此由 Wrap、WithFields 等派生出的错误仍与原哨兵错误匹配。
+type Error struct {
+	Code    ErrorCode
+	Status  int
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// NewError 创建业务错误，通常用于定义包级哨兵错误
+func NewError(code ErrorCode, status int, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	for i, f := range e.Fields {
+		sep := "; "
+		if i == 0 {
+			sep = ": "
+		}
+		msg += sep + f.Field + " " + f.Message
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap 返回以 err 为原因的副本，原因只用于日志，不会返回给客户端
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithMessage 返回替换了错误信息的副本
+func (e *Error) WithMessage(message string) *Error {
+	c := *e
+	c.Message = message
+	return &c
+}
+
+// WithStatus 返回替换了 HTTP 状态码的副本，用于同一错误在不同接口语义不同的场景
+func (e *Error) WithStatus(status int) *Error {
+	c := *e
+	c.Status = status
+	return &c
+}
+
+// WithFields 返回追加了字段错误的副本
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	c := *e
+	c.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &c
+}
+
+// HTTPStatus 返回错误对应的 HTTP 状态码，未设置时为 500
+func (e *Error) HTTPStatus() int {
+	if e.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.Status
+}
@@ -1,6 +0,0 @@
-// Package domain
-package domain
-
-type ErrorResponse struct {
-	Message string `json:"message"`
-}
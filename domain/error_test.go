@@ -0,0 +1,46 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Is(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := fmt.Errorf("login: %w", domain.ErrInvalidCredentials.WithMessage("invalid old password").Wrap(cause))
+
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	assert.ErrorIs(t, err, cause)
+	assert.NotErrorIs(t, err, domain.ErrInvalidToken)
+}
+
+func TestError_Error(t *testing.T) {
+	err := domain.ErrValidation.WithFields(
+		domain.FieldError{Field: "email", Rule: "required", Message: "is required"},
+		domain.FieldError{Field: "password", Rule: "min", Param: "8", Message: "must be at least 8 characters"},
+	).Wrap(errors.New("bind failed"))
+
+	assert.Equal(t, "request validation failed: email is required; password must be at least 8 characters: bind failed", err.Error())
+	// 派生副本不影响哨兵错误
+	assert.Empty(t, domain.ErrValidation.Fields)
+	assert.Nil(t, domain.ErrValidation.Err)
+}
+
+func TestAsError(t *testing.T) {
+	e := domain.AsError(fmt.Errorf("refresh: %w", domain.ErrUserNotFound))
+	assert.Equal(t, domain.CodeUserNotFound, e.Code)
+	assert.Equal(t, http.StatusNotFound, e.HTTPStatus())
+
+	cause := errors.New("boom")
+	e = domain.AsError(cause)
+	assert.Equal(t, domain.CodeInternal, e.Code)
+	assert.Equal(t, http.StatusInternalServerError, e.HTTPStatus())
+	assert.ErrorIs(t, e, cause)
+
+	assert.Equal(t, http.StatusInternalServerError, (&domain.Error{Code: "custom"}).HTTPStatus())
+}
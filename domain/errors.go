@@ -1,11 +1,37 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"net/http"
+)
 
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrInvalidToken       = errors.New("invalid token")
-	ErrInternalServer     = errors.New("internal server error")
+	ErrInvalidRequest     = NewError(CodeInvalidRequest, http.StatusBadRequest, "invalid request")
+	ErrValidation         = NewError(CodeValidationFailed, http.StatusBadRequest, "request validation failed")
+	ErrUnauthorized       = NewError(CodeUnauthorized, http.StatusUnauthorized, "missing or invalid authorization header")
+	ErrForbidden          = NewError(CodeForbidden, http.StatusForbidden, "insufficient permissions")
+	ErrNotFound           = NewError(CodeNotFound, http.StatusNotFound, "not found")
+	ErrPayloadTooLarge    = NewError(CodePayloadTooLarge, http.StatusRequestEntityTooLarge, "request body too large")
+	ErrUnsupportedMedia   = NewError(CodeUnsupportedMediaType, http.StatusUnsupportedMediaType, "unsupported media type")
+	ErrNotAcceptable      = NewError(CodeNotAcceptable, http.StatusNotAcceptable, "none of the accepted response formats is supported")
+	ErrRateLimited        = NewError(CodeRateLimited, http.StatusTooManyRequests, "too many requests")
+	ErrUserNotFound       = NewError(CodeUserNotFound, http.StatusNotFound, "user not found")
+	ErrInvalidCredentials = NewError(CodeInvalidCredentials, http.StatusUnauthorized, "invalid credentials")
+	ErrUserAlreadyExists  = NewError(CodeUserAlreadyExists, http.StatusConflict, "user already exists")
+	ErrInvalidToken       = NewError(CodeInvalidToken, http.StatusUnauthorized, "invalid or expired token")
+	ErrInternalServer     = NewError(CodeInternal, http.StatusInternalServerError, "internal server error")
+	ErrInvalidImage       = NewError(CodeInvalidImage, http.StatusUnprocessableEntity, "invalid image")
+	ErrUserDisabled       = NewError(CodeUserDisabled, http.StatusForbidden, "user disabled")
+	ErrInvalidRole        = NewError(CodeInvalidRole, http.StatusBadRequest, "invalid role")
+	// ErrTimeout 表示数据库等依赖在期限内未完成操作，调用方可稍后重试
+	ErrTimeout = NewError(CodeTimeout, http.StatusServiceUnavailable, "operation timed out")
 )
+
+// AsError 将任意错误转换为 *Error，无法识别的错误视为内部错误并保留原因
+func AsError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return ErrInternalServer.Wrap(err)
+}
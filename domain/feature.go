@@ -0,0 +1,11 @@
+package domain
+
+// FeatureAvatarUpload 控制头像上传接口，默认开启；对象存储故障时可在运行时关闭
+const FeatureAvatarUpload = "avatar_upload"
+
+// FeatureFlags 为已开启的功能开关集合，未列出的开关视为关闭
+type FeatureFlags map[string]bool
+
+func (f FeatureFlags) Enabled(name string) bool {
+	return f[name]
+}
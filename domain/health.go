@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+const (
+	HealthStatusOK   = "ok"
+	HealthStatusFail = "fail"
+)
+
+// HealthChecker 检查一个外部依赖是否可用，返回 nil 表示健康
+type HealthChecker interface {
+	Name() string
+	Check(c context.Context) error
+}
+
+type HealthCheckResult struct {
+	Status string `json:"status"`
+	// Error 为对外公开的失败原因（timeout 或 unavailable），详细错误只记录在日志中
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type HealthReport struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthRegistry 汇总已注册的 HealthChecker 的检查结果
+type HealthRegistry interface {
+	Register(checkers ...HealthChecker)
+	Check(c context.Context) HealthReport
+}
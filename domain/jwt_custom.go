@@ -7,6 +7,7 @@ import (
 type JwtCustomClaims struct {
 	Name string `json:"name"`
 	ID   string `json:"id"`
+	Role string `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -14,3 +15,10 @@ type JwtCustomRefreshClaims struct {
 	ID string `json:"id"`
 	jwt.RegisteredClaims
 }
+
+type JwtEmailChangeClaims struct {
+	ID       string `json:"id"`
+	OldEmail string `json:"oldEmail"`
+	NewEmail string `json:"newEmail"`
+	jwt.RegisteredClaims
+}
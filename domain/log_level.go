@@ -0,0 +1,12 @@
+package domain
+
+import "net/http"
+
+var ErrInvalidLogLevel = NewError(CodeInvalidLogLevel, http.StatusBadRequest, "invalid log level")
+
+// LogLevelManager 在运行时查询和调整日志级别，无需重启服务
+type LogLevelManager interface {
+	Level() string
+	// SetLevel 接受 trace、debug、info、warn、error、fatal、panic、disabled，非法值返回 ErrInvalidLogLevel
+	SetLevel(level string) error
+}
@@ -1,13 +1,48 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Profile struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name       string            `json:"name"`
+	Email      string            `json:"email"`
+	AvatarURL  string            `json:"avatarUrl"`
+	Attributes ProfileAttributes `json:"attributes"`
+}
+
+// ProfileUpdate 描述资料的部分更新，nil 字段表示保持不变
+type ProfileUpdate struct {
+	Name       *string
+	Attributes ProfileAttributesUpdate
+}
+
+// UserDataExport 包含系统为用户保存的全部数据，用于 GDPR 数据导出。以下字段不导出：
+// 密码只保存 bcrypt 哈希，属于凭据而非个人数据；头像以 AvatarURL 代替存储 key；
+// deleted_at、anonymized_at 只在账号删除后才有值，而已删除的账号无法再调用导出
+type UserDataExport struct {
+	ID         uint
+	Name       string
+	Email      string
+	AvatarURL  string
+	Attributes ProfileAttributes
+	Role       string
+	DisabledAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	ExportedAt time.Time
 }
 
 type ProfileUsecase interface {
 	GetProfileByID(c context.Context, userID string) (*Profile, error)
 	ChangePassword(c context.Context, userID string, oldPassword string, newPassword string) error
+	DeleteAccount(c context.Context, userID string, password string) error
+	ExportData(c context.Context, userID string) (*UserDataExport, error)
+	UpdateProfile(c context.Context, userID string, update ProfileUpdate) (*Profile, error)
+	// RequestEmailChange 向新邮箱发送确认链接并通知旧邮箱，确认前不修改用户数据
+	RequestEmailChange(c context.Context, userID string, password string, newEmail string) error
+	ConfirmEmailChange(c context.Context, token string) error
+	// UploadAvatar 校验并裁剪头像图片，生成标准尺寸缩略图后保存，替换旧头像
+	UploadAvatar(c context.Context, userID string, image []byte) (*Profile, error)
 }
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	maxDisplayNameLength = 64
+	maxMetadataKeys      = 50
+	maxMetadataBytes     = 8 << 10
+)
+
+var (
+	ErrInvalidProfileAttributes = NewError(CodeInvalidProfileAttributes, http.StatusBadRequest, "invalid profile attributes")
+
+	phonePattern       = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+)
+
+// ProfileAttributes 为用户资料的扩展属性，Metadata 供业务方存放自定义键值
+type ProfileAttributes struct {
+	DisplayName string
+	Locale      string
+	Timezone    string
+	Phone       string
+	Metadata    map[string]any
+}
+
+// Validate 校验属性取值，空字符串表示未设置；Locale 是否为合法的 BCP 47 语言标签由 usecase 校验
+func (a ProfileAttributes) Validate() error {
+	if len([]rune(a.DisplayName)) > maxDisplayNameLength {
+		return invalidAttribute("displayName", "max", strconv.Itoa(maxDisplayNameLength), fmt.Sprintf("must be at most %d characters", maxDisplayNameLength))
+	}
+	if a.Timezone != "" {
+		if _, err := time.LoadLocation(a.Timezone); err != nil {
+			return invalidAttribute("timezone", "timezone", "", "must be an IANA time zone name")
+		}
+	}
+	if a.Phone != "" && !phonePattern.MatchString(a.Phone) {
+		return invalidAttribute("phone", "e164", "", "must be in E.164 format")
+	}
+	if len(a.Metadata) > maxMetadataKeys {
+		return invalidAttribute("metadata", "max_keys", strconv.Itoa(maxMetadataKeys), fmt.Sprintf("must have at most %d keys", maxMetadataKeys))
+	}
+	for key := range a.Metadata {
+		if !metadataKeyPattern.MatchString(key) {
+			return invalidAttribute("metadata."+key, "key", "", "is not a valid metadata key")
+		}
+	}
+	if len(a.Metadata) > 0 {
+		encoded, err := json.Marshal(a.Metadata)
+		if err != nil || len(encoded) > maxMetadataBytes {
+			return invalidAttribute("metadata", "max_bytes", strconv.Itoa(maxMetadataBytes), fmt.Sprintf("must be at most %d bytes of JSON", maxMetadataBytes))
+		}
+	}
+	return nil
+}
+
+func invalidAttribute(field, rule, param, message string) error {
+	return ErrInvalidProfileAttributes.WithFields(FieldError{Field: field, Rule: rule, Param: param, Message: message})
+}
+
+// ProfileAttributesUpdate 描述扩展属性的部分更新：nil 指针保持不变，空字符串清除该属性；
+// Metadata 按键合并，值为 nil 的键被删除
+type ProfileAttributesUpdate struct {
+	DisplayName *string
+	Locale      *string
+	Timezone    *string
+	Phone       *string
+	Metadata    map[string]any
+}
+
+// Apply 返回应用更新后的新属性，不修改原值
+func (u ProfileAttributesUpdate) Apply(a ProfileAttributes) ProfileAttributes {
+	if u.DisplayName != nil {
+		a.DisplayName = *u.DisplayName
+	}
+	if u.Locale != nil {
+		a.Locale = *u.Locale
+	}
+	if u.Timezone != nil {
+		a.Timezone = *u.Timezone
+	}
+	if u.Phone != nil {
+		a.Phone = *u.Phone
+	}
+	if len(u.Metadata) > 0 {
+		merged := make(map[string]any, len(a.Metadata)+len(u.Metadata))
+		for k, v := range a.Metadata {
+			merged[k] = v
+		}
+		for k, v := range u.Metadata {
+			if v == nil {
+				delete(merged, k)
+				continue
+			}
+			merged[k] = v
+		}
+		a.Metadata = merged
+	}
+	return a
+}
@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileAttributes_Validate(t *testing.T) {
+	valid := domain.ProfileAttributes{
+		DisplayName: "Tester",
+		Locale:      "zh-CN",
+		Timezone:    "Asia/Shanghai",
+		Phone:       "+8613800138000",
+		Metadata:    map[string]any{"theme": "dark", "beta.enabled": true},
+	}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, domain.ProfileAttributes{}.Validate())
+
+	tests := map[string]domain.ProfileAttributes{
+		"display_name_too_long": {DisplayName: strings.Repeat("a", 65)},
+		"invalid_timezone":      {Timezone: "Mars/Olympus"},
+		"invalid_phone":         {Phone: "13800138000"},
+		"invalid_metadata_key":  {Metadata: map[string]any{"bad key": 1}},
+		"metadata_too_large":    {Metadata: map[string]any{"blob": strings.Repeat("x", 9000)}},
+	}
+	for name, attributes := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, attributes.Validate(), domain.ErrInvalidProfileAttributes)
+		})
+	}
+}
+
+func TestProfileAttributesUpdate_Apply(t *testing.T) {
+	current := domain.ProfileAttributes{
+		DisplayName: "Tester",
+		Locale:      "en",
+		Metadata:    map[string]any{"theme": "dark", "legacy": 1},
+	}
+	locale := "zh-CN"
+	empty := ""
+
+	updated := domain.ProfileAttributesUpdate{
+		Locale:      &locale,
+		DisplayName: &empty,
+		Metadata:    map[string]any{"legacy": nil, "beta": true},
+	}.Apply(current)
+
+	assert.Equal(t, "", updated.DisplayName)
+	assert.Equal(t, "zh-CN", updated.Locale)
+	assert.Equal(t, map[string]any{"theme": "dark", "beta": true}, updated.Metadata)
+	// 原值不被修改
+	assert.Equal(t, map[string]any{"theme": "dark", "legacy": 1}, current.Metadata)
+}
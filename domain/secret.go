@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider 从文件、Vault 等外部来源读取密钥，name 不存在时返回 ErrSecretNotFound
+type SecretProvider interface {
+	GetSecret(c context.Context, name string) (string, error)
+}
+
+// Secret 为可在运行时轮换的密钥
+type Secret interface {
+	// Current 返回当前用于签名的密钥
+	Current() string
+	// Candidates 返回验证时依次尝试的密钥：当前密钥及轮换前的上一个密钥，
+	// 使轮换前签发的 token 在过期前仍然有效
+	Candidates() []string
+}
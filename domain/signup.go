@@ -3,5 +3,7 @@ package domain
 import "context"
 
 type SignupUsecase interface {
+	// Signup 注册新用户。开启隐藏已注册邮箱模式时，成功受理返回零值 TokenPair，
+	// 调用方应提示用户查收邮件而不是直接登录。
 	Signup(c context.Context, name, email, password string) (TokenPair, error)
 }
@@ -8,4 +8,7 @@ type TokenPair struct {
 type TokenService interface {
 	GenerateTokenPair(user *User) (TokenPair, error)
 	ExtractIDFromToken(token string) (string, error)
+	GenerateEmailChangeToken(user *User, newEmail string) (string, error)
+	// ParseEmailChangeToken 校验邮箱变更 token，返回用户 ID、签发时的旧邮箱和新邮箱
+	ParseEmailChangeToken(token string) (userID, oldEmail, newEmail string, err error)
 }
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// TxManager 在事务中执行 fn，fn 收到的 context 携带该事务，
+// 使用它调用的仓储方法会自动加入事务；fn 返回错误或 panic 时回滚。
+// 已处于事务中时嵌套调用使用保存点，内层失败只回滚内层的修改。
+type TxManager interface {
+	WithinTransaction(c context.Context, fn func(c context.Context) error) error
+}
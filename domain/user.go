@@ -5,13 +5,29 @@ import (
 	"time"
 )
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// IsValidRole 判断 role 是否为系统支持的角色
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
+}
+
 type User struct {
-	ID        uint
-	Name      string
-	Email     string
-	Password  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID       uint
+	Name     string
+	Email    string
+	Password string
+	// Avatar 为头像在 BlobStore 中的 key 前缀，空表示未上传
+	Avatar     string
+	Attributes ProfileAttributes
+	Role       string
+	// DisabledAt 非空表示账号已被管理员停用
+	DisabledAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 type UserRepository interface {
@@ -19,5 +35,17 @@ type UserRepository interface {
 	Fetch(c context.Context) ([]User, error)
 	GetByEmail(c context.Context, email string) (User, error)
 	GetByID(c context.Context, id string) (User, error)
+	// GetByEmailForUpdate 与 GetByIDForUpdate 在读取时加行锁（SELECT ... FOR UPDATE），
+	// 用于 TxManager.WithinTransaction 中的读-改-写，防止并发更新相互覆盖；SQLite 下忽略加锁
+	GetByEmailForUpdate(c context.Context, email string) (User, error)
+	GetByIDForUpdate(c context.Context, id string) (User, error)
 	Update(c context.Context, user *User) error
+	// Delete 软删除用户，记录在保留期结束后由 AccountPurgeUsecase 清理
+	Delete(c context.Context, id string) error
+	// FetchPurgeable 返回在 before 之前被软删除且尚未匿名化的用户，最多 limit 条
+	FetchPurgeable(c context.Context, before time.Time, limit int) ([]User, error)
+	// Purge 物理删除已软删除的用户
+	Purge(c context.Context, id string) error
+	// Anonymize 抹除已软删除用户的个人数据，保留记录本身
+	Anonymize(c context.Context, id string) error
 }
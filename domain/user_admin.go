@@ -0,0 +1,11 @@
+package domain
+
+import "context"
+
+// UserAdminUsecase 提供运维场景下的账号管理操作，供 CLI 使用，不经过 HTTP 鉴权
+type UserAdminUsecase interface {
+	CreateUser(c context.Context, name, email, password, role string) (User, error)
+	DisableUser(c context.Context, email string) error
+	SetRole(c context.Context, email, role string) error
+	ResetPassword(c context.Context, email, newPassword string) error
+}
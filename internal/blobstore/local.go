@@ -0,0 +1,80 @@
+// Package blobstore
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+type localBlobStore struct {
+	rootDir   string
+	publicURL string
+}
+
+// NewLocalBlobStore 将文件保存在 rootDir 下，publicURL 为对外提供这些文件的地址前缀
+func NewLocalBlobStore(rootDir string, publicURL string) domain.BlobStore {
+	return &localBlobStore{
+		rootDir:   rootDir,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+func (s *localBlobStore) Put(c context.Context, key string, data io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// 先写临时文件再重命名，避免读取方看到写了一半的文件
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := io.Copy(tmp, data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localBlobStore) Delete(c context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *localBlobStore) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+func (s *localBlobStore) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(key))
+	if cleaned == "." || filepath.IsAbs(cleaned) || strings.HasPrefix(cleaned, "..") {
+		return "", fmt.Errorf("invalid blob key: %q", key)
+	}
+	return filepath.Join(s.rootDir, cleaned), nil
+}
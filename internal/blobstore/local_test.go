@@ -0,0 +1,40 @@
+package blobstore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	root := t.TempDir()
+	store := NewLocalBlobStore(root, "http://localhost:8080/uploads/")
+	ctx := context.Background()
+
+	err := store.Put(ctx, "avatars/1/64.png", strings.NewReader("png-bytes"), 9, "image/png")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(root, "avatars", "1", "64.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png-bytes", string(data))
+	assert.Equal(t, "http://localhost:8080/uploads/avatars/1/64.png", store.URL("avatars/1/64.png"))
+
+	require.NoError(t, store.Delete(ctx, "avatars/1/64.png"))
+	_, err = os.Stat(filepath.Join(root, "avatars", "1", "64.png"))
+	assert.True(t, os.IsNotExist(err))
+
+	// 删除不存在的文件不报错
+	assert.NoError(t, store.Delete(ctx, "avatars/1/64.png"))
+}
+
+func TestLocalBlobStore_RejectsTraversal(t *testing.T) {
+	store := NewLocalBlobStore(t.TempDir(), "http://localhost:8080/uploads")
+
+	err := store.Put(context.Background(), "../escape.png", strings.NewReader("x"), 1, "image/png")
+	assert.Error(t, err)
+}
@@ -0,0 +1,175 @@
+// Package database 按配置选择 Postgres、MySQL 或 SQLite 驱动并构造连接串
+package database
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-sql-driver/mysql"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverMySQL    = "mysql"
+	DriverSQLite   = "sqlite"
+)
+
+type Config struct {
+	Driver string
+	// DSN 非空时直接使用，忽略下面的连接参数。MySQL 需自行带上 parseTime=true&multiStatements=true&clientFoundRows=true
+	DSN      string
+	Host     string
+	Port     string
+	Name     string
+	User     string
+	Password string
+	// SSLMode 取值沿用 Postgres 的 disable、require、verify-ca、verify-full，MySQL 下映射为对应的 tls 参数
+	SSLMode     string
+	SSLRootCert string
+	TimeZone    string
+}
+
+// DefaultPort 返回驱动的默认端口，SQLite 返回空字符串
+func DefaultPort(driver string) string {
+	switch driver {
+	case DriverPostgres:
+		return "5432"
+	case DriverMySQL:
+		return "3306"
+	default:
+		return ""
+	}
+}
+
+// Dialector 返回 cfg 对应的 GORM 方言
+func Dialector(cfg Config) (gorm.Dialector, error) {
+	dsn, err := DSN(cfg)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Driver {
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverMySQL:
+		return gormmysql.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q", cfg.Driver)
+	}
+}
+
+// DSN 按驱动格式拼接连接串
+func DSN(cfg Config) (string, error) {
+	if cfg.DSN != "" {
+		return cfg.DSN, nil
+	}
+	port := cfg.Port
+	if port == "" {
+		port = DefaultPort(cfg.Driver)
+	}
+
+	switch cfg.Driver {
+	case DriverPostgres:
+		return postgresDSN(cfg, port), nil
+	case DriverMySQL:
+		return mysqlDSN(cfg, port)
+	case DriverSQLite:
+		return sqliteDSN(cfg), nil
+	default:
+		return "", fmt.Errorf("unsupported database driver: %q", cfg.Driver)
+	}
+}
+
+func postgresDSN(cfg Config, port string) string {
+	params := []string{
+		"host=" + quotePostgres(cfg.Host),
+		"port=" + quotePostgres(port),
+		"user=" + quotePostgres(cfg.User),
+		"password=" + quotePostgres(cfg.Password),
+		"dbname=" + quotePostgres(cfg.Name),
+	}
+	if cfg.SSLMode != "" {
+		params = append(params, "sslmode="+quotePostgres(cfg.SSLMode))
+	}
+	if cfg.SSLRootCert != "" {
+		params = append(params, "sslrootcert="+quotePostgres(cfg.SSLRootCert))
+	}
+	if cfg.TimeZone != "" {
+		params = append(params, "TimeZone="+quotePostgres(cfg.TimeZone))
+	}
+	return strings.Join(params, " ")
+}
+
+// quotePostgres 按 libpq 关键字/值格式转义，值中含空格或引号时加单引号
+func quotePostgres(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+func mysqlDSN(cfg Config, port string) (string, error) {
+	c := mysql.NewConfig()
+	c.Net = "tcp"
+	c.Addr = cfg.Host + ":" + port
+	c.User = cfg.User
+	c.Passwd = cfg.Password
+	c.DBName = cfg.Name
+	c.ParseTime = true
+	// 迁移脚本包含多条语句
+	c.MultiStatements = true
+	// RowsAffected 返回匹配行数而非实际变更行数，与其他驱动一致，仓储据此判断记录是否存在
+	c.ClientFoundRows = true
+	c.Params = map[string]string{"charset": "utf8mb4"}
+
+	if cfg.TimeZone != "" {
+		// 只影响驱动解析和发送 DATETIME 时使用的时区，不修改会话的 time_zone，避免依赖服务端时区表
+		loc, err := time.LoadLocation(cfg.TimeZone)
+		if err != nil {
+			return "", fmt.Errorf("invalid time zone %q: %w", cfg.TimeZone, err)
+		}
+		c.Loc = loc
+	}
+
+	switch cfg.SSLMode {
+	case "", "disable":
+	case "prefer":
+		c.TLSConfig = "preferred"
+	case "require":
+		c.TLSConfig = "skip-verify"
+	case "verify-ca", "verify-full":
+		if cfg.SSLRootCert != "" {
+			name, err := registerMySQLRootCert(cfg.SSLRootCert, cfg.SSLMode == "verify-full", cfg.Host)
+			if err != nil {
+				return "", err
+			}
+			c.TLSConfig = name
+		} else {
+			c.TLSConfig = "true"
+		}
+	default:
+		return "", fmt.Errorf("unsupported ssl mode for mysql: %q", cfg.SSLMode)
+	}
+	return c.FormatDSN(), nil
+}
+
+// sqliteDSN 以 Name 为数据库文件路径（":memory:" 表示内存库），并开启外键约束和写锁等待
+func sqliteDSN(cfg Config) string {
+	path := cfg.Name
+	if path == "" {
+		path = ":memory:"
+	}
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	return path + "?" + params.Encode()
+}
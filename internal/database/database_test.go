@@ -0,0 +1,73 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		expected string
+	}{
+		{
+			name: "postgres",
+			config: Config{
+				Driver: DriverPostgres, Host: "db", Name: "app", User: "app", Password: "p w'd",
+				SSLMode: "verify-full", SSLRootCert: "/certs/ca.pem", TimeZone: "UTC",
+			},
+			expected: `host=db port=5432 user=app password='p w\'d' dbname=app sslmode=verify-full sslrootcert=/certs/ca.pem TimeZone=UTC`,
+		},
+		{
+			name:     "mysql",
+			config:   Config{Driver: DriverMySQL, Host: "db", Port: "3307", Name: "app", User: "app", Password: "pwd", SSLMode: "require", TimeZone: "UTC"},
+			expected: "app:pwd@tcp(db:3307)/app?clientFoundRows=true&multiStatements=true&parseTime=true&tls=skip-verify&charset=utf8mb4",
+		},
+		{
+			name:     "sqlite",
+			config:   Config{Driver: DriverSQLite, Name: "/data/app.db"},
+			expected: "/data/app.db?_pragma=foreign_keys%281%29&_pragma=busy_timeout%285000%29",
+		},
+		{
+			name:     "explicit dsn",
+			config:   Config{Driver: DriverMySQL, DSN: "custom", Host: "ignored"},
+			expected: "custom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn, err := DSN(tt.config)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, dsn)
+		})
+	}
+}
+
+func TestDSN_Invalid(t *testing.T) {
+	tests := map[string]Config{
+		"unknown driver":   {Driver: "oracle"},
+		"mysql ssl mode":   {Driver: DriverMySQL, SSLMode: "allow"},
+		"mysql time zone":  {Driver: DriverMySQL, TimeZone: "Mars/Olympus"},
+		"mysql root cert":  {Driver: DriverMySQL, SSLMode: "verify-ca", SSLRootCert: "/nonexistent/ca.pem"},
+		"dialector driver": {Driver: ""},
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Dialector(config)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDialector_Names(t *testing.T) {
+	for _, driver := range []string{DriverPostgres, DriverMySQL, DriverSQLite} {
+		dialector, err := Dialector(Config{Driver: driver, Name: "app"})
+		require.NoError(t, err)
+		assert.Equal(t, driver, dialector.Name(), "migration directories are keyed by dialector name")
+	}
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// PoolConfig 为连接池参数，0 表示使用 database/sql 的默认值（不限制）
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// Open 连接主库并应用连接池参数。replicas 非空时注册 dbresolver：写操作和事务使用主库，
+// 其余查询分发到副本，需要读主库的查询可加上 Clauses(dbresolver.Write)
+func Open(primary gorm.Dialector, replicas []gorm.Dialector, pool PoolConfig, config *gorm.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(primary, config)
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(replicas) == 0 {
+		sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+		return db, nil
+	}
+
+	// dbresolver 的连接池参数同时作用于主库和全部副本
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}).
+		SetMaxOpenConns(pool.MaxOpenConns).
+		SetMaxIdleConns(pool.MaxIdleConns).
+		SetConnMaxLifetime(pool.ConnMaxLifetime).
+		SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+	if err := db.Use(resolver); err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("connect replicas: %w", err)
+	}
+	return db, nil
+}
+
+// ReplicaConfigs 以 primary 为模板生成副本配置，hosts 形如 "replica1" 或 "replica1:5433"
+func ReplicaConfigs(primary Config, hosts []string) ([]Config, error) {
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+	if primary.Driver == DriverSQLite {
+		return nil, fmt.Errorf("read replicas are not supported for %s", primary.Driver)
+	}
+	if primary.DSN != "" {
+		return nil, fmt.Errorf("read replicas cannot be combined with an explicit DSN")
+	}
+
+	configs := make([]Config, len(hosts))
+	for i, host := range hosts {
+		replica := primary
+		replica.Host = host
+		if h, port, err := net.SplitHostPort(host); err == nil {
+			replica.Host, replica.Port = h, port
+		}
+		configs[i] = replica
+	}
+	return configs, nil
+}
+
+type RetryConfig struct {
+	// MaxElapsed 为重试的总时长上限，0 表示只尝试一次
+	MaxElapsed     time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// OnRetry 在每次失败后、等待前调用
+	OnRetry func(attempt int, err error, wait time.Duration)
+}
+
+// Retry 按指数退避重复调用 fn 直到成功，超过 MaxElapsed 或 ctx 结束时返回最后一次的错误
+func Retry(ctx context.Context, cfg RetryConfig, fn func() error) error {
+	deadline := time.Now().Add(cfg.MaxElapsed)
+	wait := cfg.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if remaining := time.Until(deadline); remaining <= 0 {
+			return err
+		} else if wait > remaining {
+			wait = remaining
+		}
+
+		if cfg.OnRetry != nil {
+			cfg.OnRetry(attempt, err, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, cfg.MaxBackoff)
+	}
+}
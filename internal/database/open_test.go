@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	var waits []time.Duration
+	calls := 0
+	err := Retry(context.Background(), RetryConfig{
+		MaxElapsed:     time.Second,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     4 * time.Millisecond,
+		OnRetry:        func(_ int, _ error, wait time.Duration) { waits = append(waits, wait) },
+	}, func() error {
+		calls++
+		if calls < 5 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, calls)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}, waits)
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), RetryConfig{
+		MaxElapsed:     20 * time.Millisecond,
+		InitialBackoff: 5 * time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}, func() error {
+		calls++
+		return errors.New("connection refused")
+	})
+
+	assert.EqualError(t, err, "connection refused")
+	assert.Greater(t, calls, 1)
+}
+
+func TestRetry_NoBudget(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), RetryConfig{}, func() error {
+		calls++
+		return errors.New("connection refused")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestReplicaConfigs(t *testing.T) {
+	primary := Config{Driver: DriverPostgres, Host: "primary", Port: "5432", Name: "app", User: "app"}
+
+	replicas, err := ReplicaConfigs(primary, []string{"replica1", "replica2:5433"})
+	require.NoError(t, err)
+	require.Len(t, replicas, 2)
+	assert.Equal(t, "replica1", replicas[0].Host)
+	assert.Equal(t, "5432", replicas[0].Port)
+	assert.Equal(t, "replica2", replicas[1].Host)
+	assert.Equal(t, "5433", replicas[1].Port)
+	assert.Equal(t, "app", replicas[1].User)
+
+	_, err = ReplicaConfigs(Config{Driver: DriverSQLite}, []string{"replica"})
+	assert.Error(t, err)
+	_, err = ReplicaConfigs(Config{Driver: DriverMySQL, DSN: "x"}, []string{"replica"})
+	assert.Error(t, err)
+}
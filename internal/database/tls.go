@@ -0,0 +1,57 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const mysqlTLSConfigName = "custom-root-ca"
+
+// registerMySQLRootCert 以 rootCert 为信任根注册 MySQL TLS 配置，verifyHost 为 false 时只校验证书链（verify-ca）
+func registerMySQLRootCert(rootCert string, verifyHost bool, host string) (string, error) {
+	pem, err := os.ReadFile(rootCert)
+	if err != nil {
+		return "", fmt.Errorf("read ssl root cert: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return "", errors.New("ssl root cert contains no certificates")
+	}
+
+	config := &tls.Config{RootCAs: pool, ServerName: host, MinVersion: tls.VersionTLS12}
+	if !verifyHost {
+		config.InsecureSkipVerify = true
+		config.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyChain(pool, rawCerts)
+		}
+	}
+	if err := mysql.RegisterTLSConfig(mysqlTLSConfigName, config); err != nil {
+		return "", err
+	}
+	return mysqlTLSConfigName, nil
+}
+
+func verifyChain(roots *x509.CertPool, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.New("server presented no certificate")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+	return err
+}
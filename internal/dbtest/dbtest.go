@@ -0,0 +1,251 @@
+// Package dbtest 为仓储集成测试提供已执行迁移的数据库，每个测试运行在独立事务中，结束时回滚。
+//
+// 数据库由环境变量选择：
+//   - 未设置 TEST_DB_DRIVER：与生产环境一致使用内嵌 Postgres；以 root 运行或内嵌 Postgres 无法启动
+//     （如离线无法下载）时退回 SQLite 并在标准错误输出原因
+//   - TEST_DB_DRIVER=sqlite：在临时目录创建 SQLite 文件，无需任何外部服务
+//   - TEST_DB_DRIVER=postgres 且未设置 TEST_DB_DSN：下载并启动内嵌 Postgres（不能以 root 运行），失败时直接报错
+//   - 设置了 TEST_DB_DSN：连接已有的 postgres 或 mysql 数据库
+package dbtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"github.com/horaoen/go-backend-clean-architecture/internal/database"
+	"github.com/horaoen/go-backend-clean-architecture/internal/migrate"
+	"github.com/horaoen/go-backend-clean-architecture/repository/migrations"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	envDriver = "TEST_DB_DRIVER"
+	envDSN    = "TEST_DB_DSN"
+)
+
+// Server 为一个测试包共享的数据库
+type Server struct {
+	db      *gorm.DB
+	cleanup []func()
+}
+
+var shared *Server
+
+// Main 在测试包的 TestMain 中调用：启动数据库并执行迁移，运行全部测试后清理
+func Main(m *testing.M) {
+	srv, err := Start()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dbtest:", err)
+		os.Exit(1)
+	}
+	shared = srv
+	code := m.Run()
+	srv.Close()
+	os.Exit(code)
+}
+
+// DB 返回 Main 启动的数据库上的一个事务，测试结束时回滚
+func DB(t testing.TB) *gorm.DB {
+	t.Helper()
+	if shared == nil {
+		t.Fatal("dbtest: call dbtest.Main from TestMain first")
+	}
+	return shared.DB(t)
+}
+
+// Start 按环境变量准备数据库并执行全部迁移
+func Start() (*Server, error) {
+	srv := &Server{}
+	cfg, err := srv.config()
+	if err != nil {
+		srv.Close()
+		return nil, err
+	}
+	dialector, err := database.Dialector(cfg)
+	if err != nil {
+		srv.Close()
+		return nil, err
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		srv.Close()
+		return nil, err
+	}
+	srv.db = db
+	srv.onClose(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	if err := Migrate(db); err != nil {
+		srv.Close()
+		return nil, fmt.Errorf("migrate %s: %w", cfg.Driver, err)
+	}
+	return srv, nil
+}
+
+// DB 开启一个事务并在测试结束时回滚，测试之间互不可见；
+// 事务内再调用 Transaction 会使用保存点，因此 TxManager 的行为与生产环境一致
+func (s *Server) DB(t testing.TB) *gorm.DB {
+	t.Helper()
+	tx := s.db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("dbtest: begin: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// Close 关闭连接并清理内嵌数据库和临时文件
+func (s *Server) Close() {
+	for i := len(s.cleanup) - 1; i >= 0; i-- {
+		s.cleanup[i]()
+	}
+	s.cleanup = nil
+}
+
+// Insert 直接写入 fixture 记录，适合准备仓储接口无法构造的数据（如已软删除的行）
+func Insert(t testing.TB, db *gorm.DB, records ...any) {
+	t.Helper()
+	for _, record := range records {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("dbtest: insert fixture %T: %v", record, err)
+		}
+	}
+}
+
+func (s *Server) onClose(fn func()) {
+	s.cleanup = append(s.cleanup, fn)
+}
+
+func (s *Server) config() (database.Config, error) {
+	driver := os.Getenv(envDriver)
+	dsn := os.Getenv(envDSN)
+
+	switch {
+	case driver == "" && dsn == "":
+		return s.defaultConfig()
+	case dsn != "":
+		if driver == "" {
+			driver = database.DriverPostgres
+		}
+		return database.Config{Driver: driver, DSN: dsn}, nil
+	case driver == database.DriverSQLite:
+		return s.sqliteConfig()
+	case driver == database.DriverPostgres:
+		return s.startEmbeddedPostgres()
+	default:
+		return database.Config{}, fmt.Errorf("%s=%s requires %s", envDriver, driver, envDSN)
+	}
+}
+
+// defaultConfig 优先使用内嵌 Postgres，使部分索引、行锁等方言差异默认就被覆盖
+func (s *Server) defaultConfig() (database.Config, error) {
+	// initdb 拒绝以 root 运行
+	if os.Geteuid() == 0 {
+		fmt.Fprintln(os.Stderr, "dbtest: running as root, embedded postgres unavailable; falling back to sqlite")
+		return s.sqliteConfig()
+	}
+	cfg, err := s.startEmbeddedPostgres()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dbtest: %v; falling back to sqlite (set %s=postgres to fail instead)\n", err, envDriver)
+		return s.sqliteConfig()
+	}
+	return cfg, nil
+}
+
+func (s *Server) sqliteConfig() (database.Config, error) {
+	dir, err := s.tempDir()
+	if err != nil {
+		return database.Config{}, err
+	}
+	return database.Config{Driver: database.DriverSQLite, Name: filepath.Join(dir, "test.db")}, nil
+}
+
+func (s *Server) startEmbeddedPostgres() (database.Config, error) {
+	dir, err := s.tempDir()
+	if err != nil {
+		return database.Config{}, err
+	}
+	port, err := freePort()
+	if err != nil {
+		return database.Config{}, err
+	}
+
+	cfg := database.Config{
+		Driver:   database.DriverPostgres,
+		Host:     "localhost",
+		Port:     fmt.Sprint(port),
+		Name:     "app_test",
+		User:     "app",
+		Password: "app",
+		SSLMode:  "disable",
+		TimeZone: "UTC",
+	}
+	pg := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
+		Port(uint32(port)).
+		Database(cfg.Name).
+		Username(cfg.User).
+		Password(cfg.Password).
+		RuntimePath(filepath.Join(dir, "runtime")).
+		Logger(io.Discard))
+	if err := pg.Start(); err != nil {
+		return database.Config{}, fmt.Errorf("start embedded postgres: %w", err)
+	}
+	s.onClose(func() { _ = pg.Stop() })
+	return cfg, nil
+}
+
+func (s *Server) tempDir() (string, error) {
+	dir, err := os.MkdirTemp("", "dbtest-")
+	if err != nil {
+		return "", err
+	}
+	s.onClose(func() { _ = os.RemoveAll(dir) })
+	return dir, nil
+}
+
+// Migrate 对 db 执行全部迁移，供需要额外数据库的测试（如读写分离）使用
+func Migrate(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	dialect, err := migrate.DialectFor(db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	files, err := fs.Sub(migrations.FS, db.Dialector.Name())
+	if err != nil {
+		return err
+	}
+	migrator, err := migrate.New(sqlDB, dialect, files)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(context.Background())
+	return err
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = l.Close() }()
+	addr, ok := l.Addr().(*net.TCPAddr)
+	if !ok {
+		return 0, errors.New("unexpected listener address")
+	}
+	return addr.Port, nil
+}
@@ -0,0 +1,54 @@
+// Package dynconf 提供可在运行时原子替换并通知订阅者的配置值，用于配置热更新
+package dynconf
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Change 为配置值变更事件
+type Change[T any] struct {
+	Old T
+	New T
+}
+
+// Value 保存类型为 T 的配置值。读取无锁，写入时原子替换并按订阅顺序通知订阅者
+type Value[T any] struct {
+	current     atomic.Pointer[T]
+	mu          sync.Mutex
+	subscribers []func(Change[T])
+}
+
+func NewValue[T any](initial T) *Value[T] {
+	v := &Value[T]{}
+	v.current.Store(&initial)
+	return v
+}
+
+func (v *Value[T]) Load() T {
+	return *v.current.Load()
+}
+
+// Subscribe 注册变更回调，回调在 Store 的调用方 goroutine 中同步执行
+func (v *Value[T]) Subscribe(fn func(Change[T])) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.subscribers = append(v.subscribers, fn)
+}
+
+// Store 替换配置值并通知订阅者，新值与旧值相等时不做任何事并返回 false
+func (v *Value[T]) Store(value T) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	old := v.Load()
+	if reflect.DeepEqual(old, value) {
+		return false
+	}
+	v.current.Store(&value)
+	for _, fn := range v.subscribers {
+		fn(Change[T]{Old: old, New: value})
+	}
+	return true
+}
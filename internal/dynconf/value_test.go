@@ -0,0 +1,38 @@
+package dynconf
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue_StoreNotifiesSubscribers(t *testing.T) {
+	v := NewValue([]string{"a"})
+	var changes []Change[[]string]
+	v.Subscribe(func(c Change[[]string]) { changes = append(changes, c) })
+
+	assert.False(t, v.Store([]string{"a"}), "equal value is ignored")
+	assert.True(t, v.Store([]string{"a", "b"}))
+
+	assert.Equal(t, []string{"a", "b"}, v.Load())
+	assert.Equal(t, []Change[[]string]{{Old: []string{"a"}, New: []string{"a", "b"}}}, changes)
+}
+
+func TestValue_ConcurrentAccess(t *testing.T) {
+	v := NewValue(0)
+	var wg sync.WaitGroup
+	for i := 1; i <= 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			v.Store(i)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = v.Load()
+		}()
+	}
+	wg.Wait()
+	assert.Positive(t, v.Load())
+}
@@ -0,0 +1,34 @@
+// Package gormhook 在 GORM 各类操作前后注册回调，供追踪、指标等插件共用
+package gormhook
+
+import "gorm.io/gorm"
+
+// Hook 根据操作名（create、query、update、delete、row、raw）返回对应的回调
+type Hook func(operation string) func(*gorm.DB)
+
+// Register 在 GORM 内置的 create、query、update、delete、row、raw 回调前后分别注册 before 和 after，
+// 回调名为 "<name>:before_<operation>" 和 "<name>:after_<operation>"
+func Register(db *gorm.DB, name string, before, after Hook) error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, h := range hooks {
+		if err := h.before(name+":before_"+h.operation, before(h.operation)); err != nil {
+			return err
+		}
+		if err := h.after(name+":after_"+h.operation, after(h.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
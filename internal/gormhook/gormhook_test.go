@@ -0,0 +1,41 @@
+package gormhook
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type item struct {
+	ID   uint
+	Name string
+}
+
+func TestRegister(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&item{}))
+
+	var calls []string
+	record := func(phase string) Hook {
+		return func(operation string) func(*gorm.DB) {
+			return func(*gorm.DB) { calls = append(calls, phase+"_"+operation) }
+		}
+	}
+	require.NoError(t, Register(db, "test", record("before"), record("after")))
+
+	require.NoError(t, db.Create(&item{Name: "a"}).Error)
+	var items []item
+	require.NoError(t, db.Find(&items).Error)
+	require.NoError(t, db.Exec("DELETE FROM items").Error)
+
+	assert.Equal(t, []string{
+		"before_create", "after_create",
+		"before_query", "after_query",
+		"before_raw", "after_raw",
+	}, calls)
+}
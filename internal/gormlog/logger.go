@@ -0,0 +1,59 @@
+// Package gormlog 将 GORM 日志输出到 ctx 中的 zerolog logger，使 SQL 日志带上 request_id 和 trace_id
+package gormlog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+type logger struct {
+	slowThreshold time.Duration
+}
+
+// New 创建 GORM logger：执行出错记为 error，超过 slowThreshold 的查询记为 warn，其余 SQL 记为 debug
+func New(slowThreshold time.Duration) gormlogger.Interface {
+	return &logger{slowThreshold: slowThreshold}
+}
+
+// LogMode 不生效，日志级别统一由 zerolog 全局级别控制
+func (l *logger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l *logger) Info(ctx context.Context, msg string, args ...any) {
+	zerolog.Ctx(ctx).Info().Msgf(msg, args...)
+}
+
+func (l *logger) Warn(ctx context.Context, msg string, args ...any) {
+	zerolog.Ctx(ctx).Warn().Msgf(msg, args...)
+}
+
+func (l *logger) Error(ctx context.Context, msg string, args ...any) {
+	zerolog.Ctx(ctx).Error().Msgf(msg, args...)
+}
+
+func (l *logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	log := zerolog.Ctx(ctx)
+
+	var event *zerolog.Event
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		event = log.Error().Err(err)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		event = log.Warn().Bool("slow", true)
+	default:
+		event = log.Debug()
+	}
+	if !event.Enabled() {
+		return
+	}
+
+	sql, rows := fc()
+	event.Str("sql", sql).Int64("rows", rows).Dur("elapsed", elapsed).Msg("gorm query")
+}
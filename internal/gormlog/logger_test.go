@@ -0,0 +1,36 @@
+package gormlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestLogger_Trace(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := zerolog.New(&buf).Level(zerolog.InfoLevel).With().Str("request_id", "req-1").Logger().WithContext(context.Background())
+	l := New(100 * time.Millisecond)
+	query := func() (string, int64) { return "SELECT 1", 1 }
+
+	l.Trace(ctx, time.Now(), query, nil)
+	assert.Empty(t, buf.String(), "fast queries are logged at debug level")
+
+	l.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+	assert.Empty(t, buf.String(), "record not found is not an error")
+
+	l.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	assert.Contains(t, buf.String(), `"level":"warn"`)
+	assert.Contains(t, buf.String(), `"slow":true`)
+	assert.Contains(t, buf.String(), `"request_id":"req-1"`)
+	buf.Reset()
+
+	l.Trace(ctx, time.Now(), query, errors.New("syntax error"))
+	assert.Contains(t, buf.String(), `"level":"error"`)
+	assert.Contains(t, buf.String(), `"sql":"SELECT 1"`)
+}
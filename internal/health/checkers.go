@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+type checkerFunc struct {
+	name string
+	fn   func(context.Context) error
+}
+
+// CheckerFunc 将普通函数包装为 domain.HealthChecker
+func CheckerFunc(name string, fn func(context.Context) error) domain.HealthChecker {
+	return &checkerFunc{name: name, fn: fn}
+}
+
+func (c *checkerFunc) Name() string {
+	return c.name
+}
+
+func (c *checkerFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// DBChecker 通过 Ping 检查数据库连接
+func DBChecker(db *sql.DB) domain.HealthChecker {
+	return CheckerFunc("database", db.PingContext)
+}
+
+type pendingCounter interface {
+	Pending(ctx context.Context) (int, error)
+}
+
+// MigrationChecker 检查数据库是否已执行全部迁移，存在未执行的迁移时视为未就绪。
+// 迁移只会在发布时增加，确认全部执行后不再查询数据库
+func MigrationChecker(migrator pendingCounter) domain.HealthChecker {
+	var upToDate atomic.Bool
+	return CheckerFunc("migrations", func(ctx context.Context) error {
+		if upToDate.Load() {
+			return nil
+		}
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		upToDate.Store(true)
+		return nil
+	})
+}
@@ -0,0 +1,91 @@
+// Package health 汇总各依赖的健康检查结果，供就绪探针使用
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// 对外返回的检查失败原因
+const (
+	errorTimeout     = "timeout"
+	errorUnavailable = "unavailable"
+)
+
+type registry struct {
+	mu       sync.RWMutex
+	checkers []domain.HealthChecker
+	timeout  time.Duration
+}
+
+// NewRegistry 创建检查注册表，timeout 为单个检查的最长执行时间
+func NewRegistry(timeout time.Duration) domain.HealthRegistry {
+	return &registry{timeout: timeout}
+}
+
+func (r *registry) Register(checkers ...domain.HealthChecker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, checkers...)
+}
+
+// Check 并发执行全部检查，任一检查失败则整体状态为 fail
+func (r *registry) Check(c context.Context) domain.HealthReport {
+	r.mu.RLock()
+	checkers := append([]domain.HealthChecker(nil), r.checkers...)
+	r.mu.RUnlock()
+
+	results := make([]domain.HealthCheckResult, len(checkers))
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.run(c, checker)
+		}()
+	}
+	wg.Wait()
+
+	report := domain.HealthReport{
+		Status: domain.HealthStatusOK,
+		Checks: make(map[string]domain.HealthCheckResult, len(checkers)),
+	}
+	for i, checker := range checkers {
+		if results[i].Status != domain.HealthStatusOK {
+			report.Status = domain.HealthStatusFail
+		}
+		report.Checks[checker.Name()] = results[i]
+	}
+	return report
+}
+
+func (r *registry) run(c context.Context, checker domain.HealthChecker) domain.HealthCheckResult {
+	ctx, cancel := context.WithTimeout(c, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := domain.HealthCheckResult{
+		Status:   domain.HealthStatusOK,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		// 探针接口对外公开，原始错误可能包含主机名、DSN 等信息，只写入日志
+		log.Ctx(ctx).Warn().Err(err).Str("check", checker.Name()).Msg("health check failed")
+		result.Status = domain.HealthStatusFail
+		result.Error = errorSummary(err)
+	}
+	return result
+}
+
+func errorSummary(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errorTimeout
+	}
+	return errorUnavailable
+}
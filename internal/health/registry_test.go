@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakePending int
+
+func (f fakePending) Pending(context.Context) (int, error) {
+	return int(f), nil
+}
+
+type countingPending struct {
+	pending []int
+	calls   int
+}
+
+func (c *countingPending) Pending(context.Context) (int, error) {
+	c.calls++
+	return c.pending[min(c.calls, len(c.pending))-1], nil
+}
+
+func TestRegistry_Check(t *testing.T) {
+	t.Run("all_ok", func(t *testing.T) {
+		r := NewRegistry(time.Second)
+		r.Register(
+			CheckerFunc("a", func(context.Context) error { return nil }),
+			MigrationChecker(fakePending(0)),
+		)
+
+		report := r.Check(context.Background())
+
+		assert.Equal(t, domain.HealthStatusOK, report.Status)
+		assert.Len(t, report.Checks, 2)
+		assert.Equal(t, domain.HealthStatusOK, report.Checks["migrations"].Status)
+	})
+
+	t.Run("one_failing", func(t *testing.T) {
+		r := NewRegistry(time.Second)
+		r.Register(
+			CheckerFunc("a", func(context.Context) error { return nil }),
+			CheckerFunc("b", func(context.Context) error { return errors.New("down") }),
+			MigrationChecker(fakePending(2)),
+		)
+
+		report := r.Check(context.Background())
+
+		assert.Equal(t, domain.HealthStatusFail, report.Status)
+		assert.Equal(t, domain.HealthStatusOK, report.Checks["a"].Status)
+		assert.Equal(t, "unavailable", report.Checks["b"].Error, "raw errors are not exposed")
+		assert.Equal(t, "unavailable", report.Checks["migrations"].Error)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		r := NewRegistry(20 * time.Millisecond)
+		r.Register(CheckerFunc("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+
+		report := r.Check(context.Background())
+
+		assert.Equal(t, domain.HealthStatusFail, report.Status)
+		assert.Equal(t, "timeout", report.Checks["slow"].Error)
+	})
+}
+
+func TestMigrationChecker(t *testing.T) {
+	migrator := &countingPending{pending: []int{1, 0}}
+	checker := MigrationChecker(migrator)
+	ctx := context.Background()
+
+	assert.EqualError(t, checker.Check(ctx), "1 pending migrations")
+	assert.NoError(t, checker.Check(ctx))
+	assert.NoError(t, checker.Check(ctx))
+	assert.Equal(t, 2, migrator.calls, "stops querying once up to date")
+}
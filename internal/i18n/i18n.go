@@ -0,0 +1,92 @@
+// Package i18n 负责 Accept-Language 协商，并把面向客户端的英文消息翻译为请求语言。
+// 消息以英文原文为键，未收录的消息原样返回
+package i18n
+
+import (
+	"context"
+	"strings"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Default 为未携带或无法匹配 Accept-Language 时使用的语言
+var Default = language.English
+
+// Supported 为支持的语言，第一个为默认语言
+var Supported = []language.Tag{language.English, language.Chinese}
+
+var (
+	matcher  = language.NewMatcher(Supported)
+	messages = newCatalog()
+)
+
+type contextKey struct{}
+
+func newCatalog() catalog.Catalog {
+	b := catalog.NewBuilder(catalog.Fallback(Default))
+	for key, msg := range zhMessages {
+		_ = b.SetString(language.Chinese, key, msg)
+	}
+	return b
+}
+
+// Match 按 Accept-Language 头选择最合适的支持语言
+func Match(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, i, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return Supported[i]
+}
+
+func NewContext(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, contextKey{}, tag)
+}
+
+// FromContext 返回 ctx 中协商出的语言，不存在时返回 Default
+func FromContext(ctx context.Context) language.Tag {
+	if tag, ok := ctx.Value(contextKey{}).(language.Tag); ok {
+		return tag
+	}
+	return Default
+}
+
+// Translate 返回 msg 在 tag 语言下的译文，msg 为英文原文，可包含 fmt 占位符
+func Translate(tag language.Tag, msg string, args ...any) string {
+	return message.NewPrinter(tag, message.Catalog(messages)).Sprintf(msg, args...)
+}
+
+// fieldMessages 为校验规则对应的英文消息，%s 为规则参数
+var fieldMessages = map[string]string{
+	"unknown":   "is not a recognized field",
+	"type":      "has an invalid type",
+	"required":  "is required",
+	"email":     "must be a valid email address",
+	"min":       "must be at least %s characters",
+	"max":       "must be at most %s characters",
+	"oneof":     "must be one of [%s]",
+	"bcp47":     "must be a BCP 47 language tag",
+	"timezone":  "must be an IANA time zone name",
+	"e164":      "must be in E.164 format",
+	"max_keys":  "must have at most %s keys",
+	"key":       "is not a valid metadata key",
+	"max_bytes": "must be at most %s bytes of JSON",
+}
+
+// FieldMessage 返回校验规则 rule 在 tag 语言下的字段错误消息，未知规则返回 false
+func FieldMessage(tag language.Tag, rule, param string) (string, bool) {
+	msg, ok := fieldMessages[rule]
+	if !ok {
+		return "", false
+	}
+	if !strings.Contains(msg, "%s") {
+		return Translate(tag, msg), true
+	}
+	return Translate(tag, msg, param), true
+}
@@ -0,0 +1,68 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+)
+
+func TestMatch(t *testing.T) {
+	tests := map[string]language.Tag{
+		"":                        language.English,
+		"zh-CN,zh;q=0.9,en;q=0.8": language.Chinese,
+		"zh":                      language.Chinese,
+		"en-US,en;q=0.9":          language.English,
+		"fr-FR,zh;q=0.5":          language.Chinese,
+		"de-DE":                   language.English,
+		"not a header;;":          language.English,
+	}
+	for header, expected := range tests {
+		t.Run(header, func(t *testing.T) {
+			assert.Equal(t, expected, Match(header))
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, language.Chinese, FromContext(NewContext(context.Background(), language.Chinese)))
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "用户不存在", Translate(language.Chinese, "user not found"))
+	assert.Equal(t, "user not found", Translate(language.English, "user not found"))
+	// 未收录的消息原样返回
+	assert.Equal(t, "something new", Translate(language.Chinese, "something new"))
+}
+
+func TestFieldMessage(t *testing.T) {
+	msg, ok := FieldMessage(language.Chinese, "min", "6")
+	assert.True(t, ok)
+	assert.Equal(t, "长度不能少于 6 个字符", msg)
+
+	msg, ok = FieldMessage(language.English, "required", "")
+	assert.True(t, ok)
+	assert.Equal(t, "is required", msg)
+
+	_, ok = FieldMessage(language.English, "uuid", "")
+	assert.False(t, ok)
+}
+
+func TestCatalogComplete(t *testing.T) {
+	sentinels := []*domain.Error{
+		domain.ErrInvalidRequest, domain.ErrValidation, domain.ErrUnauthorized, domain.ErrForbidden,
+		domain.ErrNotFound, domain.ErrPayloadTooLarge, domain.ErrUnsupportedMedia, domain.ErrNotAcceptable, domain.ErrRateLimited,
+		domain.ErrUserNotFound, domain.ErrInvalidCredentials, domain.ErrUserAlreadyExists, domain.ErrInvalidToken,
+		domain.ErrInternalServer, domain.ErrInvalidImage, domain.ErrUserDisabled, domain.ErrInvalidRole,
+		domain.ErrTimeout, domain.ErrInvalidLogLevel, domain.ErrInvalidProfileAttributes,
+	}
+	for _, e := range sentinels {
+		assert.Contains(t, zhMessages, e.Message, "missing zh translation for %s", e.Code)
+	}
+	for rule, msg := range fieldMessages {
+		assert.Contains(t, zhMessages, msg, "missing zh translation for rule %q", rule)
+	}
+}
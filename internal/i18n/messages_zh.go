@@ -0,0 +1,46 @@
+package i18n
+
+// zhMessages 为简体中文译文，键为英文原文。新增面向客户端的错误消息时需同步补充
+var zhMessages = map[string]string{
+	// 业务错误
+	"invalid request":                                    "请求无效",
+	"request validation failed":                          "请求参数校验失败",
+	"missing or invalid authorization header":            "缺少或无效的 Authorization 请求头",
+	"insufficient permissions":                           "权限不足",
+	"not found":                                          "资源不存在",
+	"request body too large":                             "请求体过大",
+	"unsupported media type":                             "不支持的媒体类型",
+	"none of the accepted response formats is supported": "不支持 Accept 中的任何响应格式",
+	"too many requests":                                  "请求过于频繁，请稍后再试",
+	"user not found":                                     "用户不存在",
+	"invalid credentials":                                "邮箱或密码错误",
+	"user already exists":                                "用户已存在",
+	"invalid or expired token":                           "令牌无效或已过期",
+	"internal server error":                              "服务器内部错误",
+	"invalid image":                                      "图片无效",
+	"user disabled":                                      "账号已被禁用",
+	"invalid role":                                       "角色无效",
+	"operation timed out":                                "操作超时，请稍后重试",
+	"invalid log level":                                  "日志级别无效",
+	"invalid profile attributes":                         "资料属性无效",
+	"invalid old password":                               "原密码错误",
+	"email already in use":                               "邮箱已被使用",
+	"avatar file too large":                              "头像文件过大",
+	"avatar must be a jpeg, png, gif or webp image":      "头像必须为 jpeg、png、gif 或 webp 格式的图片",
+
+	// 字段校验
+	"is not a recognized field":        "不是可识别的字段",
+	"has an invalid type":              "类型不正确",
+	"is invalid":                       "无效",
+	"is required":                      "不能为空",
+	"must be a valid email address":    "必须是有效的邮箱地址",
+	"must be at least %s characters":   "长度不能少于 %s 个字符",
+	"must be at most %s characters":    "长度不能超过 %s 个字符",
+	"must be one of [%s]":              "必须是 [%s] 之一",
+	"must be a BCP 47 language tag":    "必须是 BCP 47 语言标签",
+	"must be an IANA time zone name":   "必须是 IANA 时区名称",
+	"must be in E.164 format":          "必须为 E.164 格式",
+	"must have at most %s keys":        "键数量不能超过 %s 个",
+	"is not a valid metadata key":      "不是有效的元数据键",
+	"must be at most %s bytes of JSON": "JSON 大小不能超过 %s 字节",
+}
@@ -0,0 +1,28 @@
+package logging
+
+import (
+	"fmt"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog"
+)
+
+type levelManager struct{}
+
+// NewLevelManager 返回调整 zerolog 全局日志级别的 domain.LogLevelManager
+func NewLevelManager() domain.LogLevelManager {
+	return levelManager{}
+}
+
+func (levelManager) Level() string {
+	return zerolog.GlobalLevel().String()
+}
+
+func (levelManager) SetLevel(level string) error {
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil || level == "" {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidLogLevel, level)
+	}
+	zerolog.SetGlobalLevel(parsed)
+	return nil
+}
@@ -0,0 +1,94 @@
+// Package logging 根据配置构建全局 zerolog logger：输出格式、文件滚动、采样和运行时级别调整
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+type Config struct {
+	Level zerolog.Level
+	// Format 为 json 或 console，console 输出带颜色的可读格式，适合本地开发
+	Format string
+	// File 为日志文件路径，为空时只输出到标准输出
+	File           string
+	FileMaxSizeMB  int
+	FileMaxAgeDay  int
+	FileMaxBackups int
+	// SampleBurst 大于 0 时开启采样：每个 SamplePeriod 内 debug/info 级别日志前 SampleBurst 条全部输出，
+	// 之后每 SampleThereafter 条输出 1 条；warn 及以上级别不采样
+	SampleBurst      uint32
+	SamplePeriod     time.Duration
+	SampleThereafter uint32
+}
+
+// New 按配置创建 logger，返回的 io.Closer 用于退出时关闭日志文件
+func New(config Config) (zerolog.Logger, io.Closer, error) {
+	var writers []io.Writer
+	switch config.Format {
+	case FormatConsole:
+		writers = append(writers, zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339})
+	case FormatJSON, "":
+		writers = append(writers, os.Stdout)
+	default:
+		return zerolog.Logger{}, nil, fmt.Errorf("unsupported log format: %q", config.Format)
+	}
+
+	var closer io.Closer = nopCloser{}
+	if config.File != "" {
+		file := &lumberjack.Logger{
+			Filename:   config.File,
+			MaxSize:    config.FileMaxSizeMB,
+			MaxAge:     config.FileMaxAgeDay,
+			MaxBackups: config.FileMaxBackups,
+			Compress:   true,
+		}
+		// 文件始终使用 JSON 格式，便于采集
+		writers = append(writers, file)
+		closer = file
+	}
+
+	logger := zerolog.New(zerolog.MultiLevelWriter(writers...)).With().Timestamp().Logger()
+	if config.SampleBurst > 0 {
+		logger = logger.Sample(newSampler(config))
+	}
+	zerolog.SetGlobalLevel(config.Level)
+	return logger, closer, nil
+}
+
+func newSampler(config Config) zerolog.Sampler {
+	thereafter := config.SampleThereafter
+	if thereafter == 0 {
+		thereafter = 1
+	}
+	period := config.SamplePeriod
+	if period <= 0 {
+		period = time.Second
+	}
+	sampler := &zerolog.BurstSampler{
+		Burst:       config.SampleBurst,
+		Period:      period,
+		NextSampler: &zerolog.BasicSampler{N: thereafter},
+	}
+	return zerolog.LevelSampler{
+		TraceSampler: sampler,
+		DebugSampler: sampler,
+		InfoSampler:  sampler,
+	}
+}
+
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
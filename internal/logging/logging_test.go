@@ -0,0 +1,78 @@
+package logging
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_FileOutputWithSampling(t *testing.T) {
+	t.Cleanup(func() { zerolog.SetGlobalLevel(zerolog.TraceLevel) })
+	file := filepath.Join(t.TempDir(), "app.log")
+
+	logger, closer, err := New(Config{
+		Level:            zerolog.InfoLevel,
+		Format:           FormatJSON,
+		File:             file,
+		FileMaxSizeMB:    1,
+		SampleBurst:      3,
+		SamplePeriod:     time.Hour,
+		SampleThereafter: 1000,
+	})
+	require.NoError(t, err)
+
+	for range 10 {
+		logger.Info().Msg("high volume")
+	}
+	logger.Debug().Msg("below level")
+	logger.Error().Msg("not sampled")
+	logger.Error().Msg("not sampled")
+	require.NoError(t, closer.Close())
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+
+	var info, errs int
+	for _, line := range lines {
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		switch entry["level"] {
+		case "info":
+			info++
+		case "error":
+			errs++
+		default:
+			t.Fatalf("unexpected level in %s", line)
+		}
+	}
+	// 突发额度内 3 条，超出后每 1000 条采样 1 条（计数从第一条开始）
+	assert.Equal(t, 4, info)
+	assert.Equal(t, 2, errs)
+}
+
+func TestNew_UnknownFormat(t *testing.T) {
+	_, _, err := New(Config{Format: "xml"})
+	assert.Error(t, err)
+}
+
+func TestLevelManager(t *testing.T) {
+	t.Cleanup(func() { zerolog.SetGlobalLevel(zerolog.TraceLevel) })
+	m := NewLevelManager()
+
+	require.NoError(t, m.SetLevel("warn"))
+	assert.Equal(t, "warn", m.Level())
+	assert.Equal(t, zerolog.WarnLevel, zerolog.GlobalLevel())
+
+	assert.ErrorIs(t, m.SetLevel("verbose"), domain.ErrInvalidLogLevel)
+	assert.ErrorIs(t, m.SetLevel(""), domain.ErrInvalidLogLevel)
+	assert.Equal(t, "warn", m.Level())
+}
@@ -0,0 +1,25 @@
+// Package mailer
+package mailer
+
+import (
+	"context"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog/log"
+)
+
+type logMailer struct{}
+
+// NewLogMailer 返回一个仅记录日志的 Mailer，用于开发环境或尚未接入邮件服务时。
+// 只记录收件人和主题：正文中可能含有邮箱确认链接等凭据，任何可读日志的人都能凭此接管账号
+func NewLogMailer() domain.Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(c context.Context, mail domain.Mail) error {
+	log.Ctx(c).Info().
+		Str("to", mail.To).
+		Str("subject", mail.Subject).
+		Msg("mail sent")
+	return nil
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// failureReason 将用例错误归类为有限的标签值
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		return "invalid_credentials"
+	case errors.Is(err, domain.ErrUserDisabled):
+		return "user_disabled"
+	case errors.Is(err, domain.ErrInvalidToken):
+		return "invalid_token"
+	case errors.Is(err, domain.ErrUserNotFound):
+		return "user_not_found"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "internal"
+	}
+}
+
+type loginUsecase struct {
+	next    domain.LoginUsecase
+	metrics *Metrics
+}
+
+// InstrumentLogin 为登录用例统计成功和按原因分类的失败次数
+func InstrumentLogin(m *Metrics, next domain.LoginUsecase) domain.LoginUsecase {
+	return &loginUsecase{next: next, metrics: m}
+}
+
+func (u *loginUsecase) Login(c context.Context, email, password string) (domain.TokenPair, error) {
+	tokens, err := u.next.Login(c, email, password)
+	if err != nil {
+		u.metrics.loginAttempts.WithLabelValues(resultFailure, failureReason(err)).Inc()
+	} else {
+		u.metrics.loginAttempts.WithLabelValues(resultSuccess, "").Inc()
+	}
+	return tokens, err
+}
+
+type refreshTokenUsecase struct {
+	next    domain.RefreshTokenUsecase
+	metrics *Metrics
+}
+
+// InstrumentRefreshToken 为刷新 token 用例统计成功和按原因分类的失败次数
+func InstrumentRefreshToken(m *Metrics, next domain.RefreshTokenUsecase) domain.RefreshTokenUsecase {
+	return &refreshTokenUsecase{next: next, metrics: m}
+}
+
+func (u *refreshTokenUsecase) Refresh(c context.Context, refreshToken string) (domain.TokenPair, error) {
+	tokens, err := u.next.Refresh(c, refreshToken)
+	if err != nil {
+		u.metrics.tokenRefreshes.WithLabelValues(resultFailure, failureReason(err)).Inc()
+	} else {
+		u.metrics.tokenRefreshes.WithLabelValues(resultSuccess, "").Inc()
+	}
+	return tokens, err
+}
+
+type tokenService struct {
+	domain.TokenService
+	metrics *Metrics
+}
+
+// InstrumentTokenService 统计签发的 token 对数量，覆盖登录、注册和刷新等所有签发路径
+func InstrumentTokenService(m *Metrics, next domain.TokenService) domain.TokenService {
+	return &tokenService{TokenService: next, metrics: m}
+}
+
+func (s *tokenService) GenerateTokenPair(user *domain.User) (domain.TokenPair, error) {
+	tokens, err := s.TokenService.GenerateTokenPair(user)
+	if err == nil {
+		s.metrics.tokensIssued.Inc()
+	}
+	return tokens, err
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubLogin struct {
+	err error
+}
+
+func (s stubLogin) Login(context.Context, string, string) (domain.TokenPair, error) {
+	return domain.TokenPair{}, s.err
+}
+
+type stubRefresh struct {
+	err error
+}
+
+func (s stubRefresh) Refresh(context.Context, string) (domain.TokenPair, error) {
+	return domain.TokenPair{}, s.err
+}
+
+func TestInstrumentLogin(t *testing.T) {
+	m := New()
+
+	_, _ = InstrumentLogin(m, stubLogin{}).Login(context.Background(), "a@example.com", "pw")
+	_, _ = InstrumentLogin(m, stubLogin{err: domain.ErrInvalidCredentials}).Login(context.Background(), "a@example.com", "pw")
+	_, _ = InstrumentLogin(m, stubLogin{err: domain.ErrInvalidCredentials}).Login(context.Background(), "a@example.com", "pw")
+	_, _ = InstrumentLogin(m, stubLogin{err: domain.ErrUserDisabled}).Login(context.Background(), "a@example.com", "pw")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.loginAttempts.WithLabelValues(resultSuccess, "")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.loginAttempts.WithLabelValues(resultFailure, "invalid_credentials")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.loginAttempts.WithLabelValues(resultFailure, "user_disabled")))
+}
+
+func TestInstrumentRefreshToken(t *testing.T) {
+	m := New()
+
+	_, _ = InstrumentRefreshToken(m, stubRefresh{}).Refresh(context.Background(), "token")
+	_, _ = InstrumentRefreshToken(m, stubRefresh{err: domain.ErrInvalidToken}).Refresh(context.Background(), "token")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenRefreshes.WithLabelValues(resultSuccess, "")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenRefreshes.WithLabelValues(resultFailure, "invalid_token")))
+}
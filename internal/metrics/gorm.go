@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/gormhook"
+	"gorm.io/gorm"
+)
+
+const startTimeKey = "metrics:start_time"
+
+type gormPlugin struct {
+	metrics *Metrics
+}
+
+// GormPlugin 返回记录每条 SQL 执行耗时的 GORM 插件
+func GormPlugin(m *Metrics) gorm.Plugin {
+	return &gormPlugin{metrics: m}
+}
+
+func (p *gormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p *gormPlugin) Initialize(db *gorm.DB) error {
+	return gormhook.Register(db, "metrics", func(string) func(*gorm.DB) { return p.before }, p.after)
+}
+
+func (p *gormPlugin) before(db *gorm.DB) {
+	db.InstanceSet(startTimeKey, time.Now())
+}
+
+func (p *gormPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		p.metrics.dbQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,19 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute 用于未匹配任何路由的请求，避免原始路径导致标签基数失控
+const unmatchedRoute = "unmatched"
+
+// ObserveHTTPRequest 记录一次 HTTP 请求，route 应为路由模板（如 /users/:id）而非实际路径
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
@@ -0,0 +1,128 @@
+package migrate_test
+
+import (
+	"context"
+	"database/sql"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/glebarez/go-sqlite"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/migrate"
+	"github.com/horaoen/go-backend-clean-architecture/repository/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_avatar.up.sql":     {Data: []byte("ALTER TABLE users ADD COLUMN avatar TEXT;")},
+		"0002_add_avatar.down.sql":   {Data: []byte("ALTER TABLE users DROP COLUMN avatar;")},
+		"0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id BIGINT);")},
+		"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	}
+
+	loaded, err := migrate.Load(fsys)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+
+	assert.Equal(t, int64(1), loaded[0].Version)
+	assert.Equal(t, "create_users", loaded[0].Name)
+	assert.Equal(t, "DROP TABLE users;", loaded[0].Down)
+	assert.Equal(t, int64(2), loaded[1].Version)
+	assert.Equal(t, "add_avatar", loaded[1].Name)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"bad_name": {
+			"create_users.sql": {Data: []byte("SELECT 1;")},
+		},
+		"missing_up": {
+			"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		},
+		"conflicting_names": {
+			"0001_create_users.up.sql":  {Data: []byte("SELECT 1;")},
+			"0001_create_people.up.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := migrate.Load(fsys)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// 确保仓库内置的迁移文件都能被正确加载，且各方言的迁移版本保持一致
+func TestLoad_Embedded(t *testing.T) {
+	var versions [][]int64
+	for _, dialect := range []string{"postgres", "mysql", "sqlite"} {
+		files, err := fs.Sub(migrations.FS, dialect)
+		require.NoError(t, err)
+
+		loaded, err := migrate.Load(files)
+		require.NoError(t, err, dialect)
+		assert.NotEmpty(t, loaded, dialect)
+
+		var dialectVersions []int64
+		for _, m := range loaded {
+			assert.NotEmpty(t, m.Down, "%s migration %d should be reversible", dialect, m.Version)
+			dialectVersions = append(dialectVersions, m.Version)
+		}
+		versions = append(versions, dialectVersions)
+	}
+	assert.Equal(t, versions[0], versions[1], "mysql migrations match postgres")
+	assert.Equal(t, versions[0], versions[2], "sqlite migrations match postgres")
+}
+
+func TestMigrator_SQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "app.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	dialect, err := migrate.DialectFor("sqlite")
+	require.NoError(t, err)
+	files, err := fs.Sub(migrations.FS, "sqlite")
+	require.NoError(t, err)
+	m, err := migrate.New(db, dialect, files)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// 就绪检查会频繁调用 Pending，不能在数据库中建表
+	pending, err := m.Pending(ctx)
+	require.NoError(t, err)
+	var tables int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE name = 'schema_migrations'").Scan(&tables))
+	assert.Zero(t, tables, "Pending is read-only")
+
+	applied, err := m.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, pending)
+	assert.NotEmpty(t, applied)
+
+	pending, err = m.Pending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+
+	_, err = db.ExecContext(ctx, "INSERT INTO users (name, email, password, role) VALUES ('a', 'a@example.com', 'x', 'admin')")
+	require.NoError(t, err)
+
+	// 回滚到只剩建表迁移，role 列随之删除
+	reverted, err := m.Down(ctx, len(applied)-1)
+	require.NoError(t, err)
+	require.Len(t, reverted, len(applied)-1)
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, statuses[len(statuses)-1].AppliedAt)
+	assert.NotNil(t, statuses[0].AppliedAt)
+
+	_, err = m.Up(ctx)
+	require.NoError(t, err)
+	var role string
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT role FROM users WHERE email = 'a@example.com'").Scan(&role))
+	assert.Equal(t, "user", role, "re-applied column gets its default")
+}
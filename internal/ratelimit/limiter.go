@@ -0,0 +1,82 @@
+// Package ratelimit 提供按 key（通常为客户端 IP）隔离的令牌桶限流
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+	"golang.org/x/time/rate"
+)
+
+// idleTimeout 为 key 无请求后其令牌桶被回收的时间
+const idleTimeout = 10 * time.Minute
+
+type Config struct {
+	// RPS 为每个 key 每秒补充的令牌数，<= 0 表示不限流
+	RPS   float64
+	Burst int
+}
+
+type Limiter struct {
+	config *dynconf.Value[Config]
+
+	mu        sync.Mutex
+	limiters  map[string]*entry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type entry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// New 创建限流器。config 变更后已有的令牌桶被丢弃，按新配置重新计数
+func New(config *dynconf.Value[Config]) *Limiter {
+	l := &Limiter{
+		config:   config,
+		limiters: map[string]*entry{},
+		now:      time.Now,
+	}
+	config.Subscribe(func(dynconf.Change[Config]) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.limiters = map[string]*entry{}
+	})
+	return l
+}
+
+// Allow 消耗 key 的一个令牌，令牌不足时返回 false
+func (l *Limiter) Allow(key string) bool {
+	config := l.config.Load()
+	if config.RPS <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	e, ok := l.limiters[key]
+	if !ok {
+		e = &entry{limiter: rate.NewLimiter(rate.Limit(config.RPS), max(config.Burst, 1))}
+		l.limiters[key] = e
+	}
+	e.lastSeen = now
+	return e.limiter.AllowN(now, 1)
+}
+
+// sweep 定期回收长时间空闲的令牌桶，避免 key 数量无限增长
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < idleTimeout {
+		return
+	}
+	l.lastSweep = now
+	for key, e := range l.limiters {
+		if now.Sub(e.lastSeen) >= idleTimeout {
+			delete(l.limiters, key)
+		}
+	}
+}
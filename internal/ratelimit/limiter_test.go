@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/dynconf"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestLimiter(config Config) (*Limiter, *dynconf.Value[Config], *time.Time) {
+	value := dynconf.NewValue(config)
+	l := New(value)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	return l, value, &now
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	l, _, now := newTestLimiter(Config{RPS: 1, Burst: 2})
+
+	assert.True(t, l.Allow("a"))
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"), "burst exhausted")
+	assert.True(t, l.Allow("b"), "keys are isolated")
+
+	*now = now.Add(time.Second)
+	assert.True(t, l.Allow("a"), "token refilled")
+}
+
+func TestLimiter_Disabled(t *testing.T) {
+	l, _, _ := newTestLimiter(Config{})
+	for i := 0; i < 100; i++ {
+		assert.True(t, l.Allow("a"))
+	}
+}
+
+func TestLimiter_ConfigChange(t *testing.T) {
+	l, value, _ := newTestLimiter(Config{RPS: 1, Burst: 1})
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"))
+
+	value.Store(Config{RPS: 1, Burst: 3})
+
+	assert.True(t, l.Allow("a"))
+	assert.True(t, l.Allow("a"))
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"))
+}
+
+func TestLimiter_SweepsIdleKeys(t *testing.T) {
+	l, _, now := newTestLimiter(Config{RPS: 1, Burst: 1})
+	l.Allow("a")
+
+	*now = now.Add(idleTimeout)
+	l.Allow("b")
+
+	assert.NotContains(t, l.limiters, "a")
+	assert.Contains(t, l.limiters, "b")
+}
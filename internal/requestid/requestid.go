@@ -0,0 +1,44 @@
+// Package requestid 生成、校验请求 ID，并在 context.Context 中传递
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header 为请求和响应中携带请求 ID 的 HTTP 头
+const Header = "X-Request-ID"
+
+const maxLength = 128
+
+type contextKey struct{}
+
+// New 生成新的请求 ID
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid 判断上游传入的请求 ID 是否可以直接沿用：非空、长度有限且只包含可见 ASCII 字符，
+// 防止日志注入和超长字段
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext 返回 ctx 中的请求 ID，不存在时返回空字符串
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,43 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+type fileProvider struct {
+	dir string
+}
+
+// NewFileProvider 从 dir 下与密钥同名的文件读取密钥，适用于 Kubernetes 挂载的 Secret 卷：
+// Secret 更新后挂载文件随之更新，定期刷新即可生效
+func NewFileProvider(dir string) domain.SecretProvider {
+	return &fileProvider{dir: dir}
+}
+
+func (p *fileProvider) GetSecret(_ context.Context, name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid secret name: %q", name)
+	}
+	value, err := ReadFile(filepath.Join(p.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", domain.ErrSecretNotFound
+	}
+	return value, err
+}
+
+// ReadFile 读取密钥文件内容并去掉末尾换行，编辑器和 echo 写入的文件通常带有换行
+func ReadFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
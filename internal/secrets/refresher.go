@@ -0,0 +1,54 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// Refresher 定期从 SecretProvider 读取密钥并更新绑定的 Value，实现无需重启的密钥轮换
+type Refresher struct {
+	provider domain.SecretProvider
+	validate func(name, value string) error
+	bindings []binding
+}
+
+type binding struct {
+	name  string
+	value *Value
+}
+
+// NewRefresher 创建刷新器，validate 为 nil 时只拒绝空值
+func NewRefresher(provider domain.SecretProvider, validate func(name, value string) error) *Refresher {
+	return &Refresher{provider: provider, validate: validate}
+}
+
+// Bind 将名为 name 的密钥同步到 value
+func (r *Refresher) Bind(name string, value *Value) {
+	r.bindings = append(r.bindings, binding{name: name, value: value})
+}
+
+// Refresh 读取全部绑定的密钥。读取失败或未通过校验的密钥保持原值，错误汇总返回
+func (r *Refresher) Refresh(ctx context.Context) error {
+	var errs []error
+	for _, b := range r.bindings {
+		value, err := r.provider.GetSecret(ctx, b.name)
+		if err == nil && value == "" {
+			err = errors.New("empty value")
+		}
+		if err == nil && r.validate != nil {
+			err = r.validate(b.name, value)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("refresh secret %s: %w", b.name, err))
+			continue
+		}
+		if b.value.Set(value) {
+			log.Ctx(ctx).Info().Str("secret", b.name).Msg("secret rotated")
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,131 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue_Set(t *testing.T) {
+	v := Static("a")
+	assert.Equal(t, []string{"a"}, v.Candidates())
+
+	assert.False(t, v.Set("a"))
+	assert.True(t, v.Set("b"))
+	assert.Equal(t, "b", v.Current())
+	assert.Equal(t, []string{"b", "a"}, v.Candidates())
+
+	v.Set("c")
+	assert.Equal(t, []string{"c", "b"}, v.Candidates())
+}
+
+func TestFileProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ACCESS_TOKEN_SECRET"), []byte("s3cr3t\n"), 0o600))
+	p := NewFileProvider(dir)
+
+	value, err := p.GetSecret(context.Background(), "ACCESS_TOKEN_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", value)
+
+	_, err = p.GetSecret(context.Background(), "MISSING")
+	assert.ErrorIs(t, err, domain.ErrSecretNotFound)
+
+	for _, name := range []string{"", "../etc/passwd", "a/b", ".hidden"} {
+		_, err = p.GetSecret(context.Background(), name)
+		assert.Error(t, err, name)
+		assert.NotErrorIs(t, err, domain.ErrSecretNotFound, name)
+	}
+}
+
+// fakeVault 是 Vault KV v2 的最小替身，只支持读取 secret/data/app
+func fakeVault(t *testing.T, data *map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/secret/data/app" {
+			http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": *data, "metadata": map[string]any{"version": 1}}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVaultProvider(t *testing.T) {
+	data := map[string]any{"ACCESS_TOKEN_SECRET": "from-vault", "NUMBER": 1}
+	server := fakeVault(t, &data)
+
+	p, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "root", Path: "/app/"}, server.Client())
+	require.NoError(t, err)
+
+	value, err := p.GetSecret(context.Background(), "ACCESS_TOKEN_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", value)
+
+	_, err = p.GetSecret(context.Background(), "MISSING")
+	assert.ErrorIs(t, err, domain.ErrSecretNotFound)
+
+	_, err = p.GetSecret(context.Background(), "NUMBER")
+	assert.ErrorContains(t, err, "not a string")
+
+	badToken, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "wrong", Path: "app"}, server.Client())
+	require.NoError(t, err)
+	_, err = badToken.GetSecret(context.Background(), "ACCESS_TOKEN_SECRET")
+	assert.ErrorContains(t, err, "403")
+
+	missingPath, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "root", Path: "other"}, server.Client())
+	require.NoError(t, err)
+	_, err = missingPath.GetSecret(context.Background(), "ACCESS_TOKEN_SECRET")
+	assert.ErrorIs(t, err, domain.ErrSecretNotFound)
+}
+
+func TestNewVaultProvider_InvalidConfig(t *testing.T) {
+	_, err := NewVaultProvider(VaultConfig{Address: "vault:8200", Path: "app"}, nil)
+	assert.Error(t, err)
+
+	_, err = NewVaultProvider(VaultConfig{Address: "http://vault:8200"}, nil)
+	assert.Error(t, err)
+}
+
+func TestRefresher_Refresh(t *testing.T) {
+	data := map[string]any{"ACCESS_TOKEN_SECRET": "v1", "REFRESH_TOKEN_SECRET": "r1"}
+	server := fakeVault(t, &data)
+	p, err := NewVaultProvider(VaultConfig{Address: server.URL, Token: "root", Path: "app"}, server.Client())
+	require.NoError(t, err)
+
+	access, refresh := Static("v0"), Static("r1")
+	r := NewRefresher(p, func(_, value string) error {
+		if value == "weak" {
+			return errors.New("too weak")
+		}
+		return nil
+	})
+	r.Bind("ACCESS_TOKEN_SECRET", access)
+	r.Bind("REFRESH_TOKEN_SECRET", refresh)
+
+	require.NoError(t, r.Refresh(context.Background()))
+	assert.Equal(t, []string{"v1", "v0"}, access.Candidates())
+	assert.Equal(t, []string{"r1"}, refresh.Candidates())
+
+	data = map[string]any{"ACCESS_TOKEN_SECRET": "weak", "REFRESH_TOKEN_SECRET": ""}
+	err = r.Refresh(context.Background())
+	assert.ErrorContains(t, err, "ACCESS_TOKEN_SECRET: too weak")
+	assert.ErrorContains(t, err, "REFRESH_TOKEN_SECRET: empty value")
+	assert.Equal(t, "v1", access.Current(), "rejected value is not applied")
+	assert.Equal(t, "r1", refresh.Current())
+}
@@ -0,0 +1,43 @@
+// Package secrets 提供 domain.SecretProvider 的文件与 Vault 实现，以及支持轮换的密钥值
+package secrets
+
+import "sync"
+
+// Value 为并发安全的可轮换密钥，实现 domain.Secret
+type Value struct {
+	mu       sync.RWMutex
+	current  string
+	previous string
+}
+
+// Static 创建初始值为 value 的密钥
+func Static(value string) *Value {
+	return &Value{current: value}
+}
+
+func (v *Value) Current() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.current
+}
+
+func (v *Value) Candidates() []string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	if v.previous == "" {
+		return []string{v.current}
+	}
+	return []string{v.current, v.previous}
+}
+
+// Set 替换当前密钥并保留旧值用于验证，值未变化时返回 false
+func (v *Value) Set(value string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if value == v.current {
+		return false
+	}
+	v.previous = v.current
+	v.current = value
+	return true
+}
@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+type VaultConfig struct {
+	// Address 形如 https://vault.example.com:8200
+	Address string
+	Token   string
+	// Mount 为 KV v2 引擎的挂载路径，默认 secret
+	Mount string
+	// Path 为密钥所在路径，每个密钥对应其中的一个字段
+	Path string
+}
+
+type vaultProvider struct {
+	config VaultConfig
+	client *http.Client
+}
+
+// NewVaultProvider 通过 HTTP API 读取 HashiCorp Vault KV v2 引擎中的密钥
+func NewVaultProvider(config VaultConfig, client *http.Client) (domain.SecretProvider, error) {
+	address, err := url.Parse(strings.TrimSuffix(config.Address, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if address.Scheme == "" || address.Host == "" {
+		return nil, fmt.Errorf("invalid vault address: %q", config.Address)
+	}
+	if config.Path == "" {
+		return nil, fmt.Errorf("vault secret path is required")
+	}
+	config.Address = address.String()
+	if config.Mount == "" {
+		config.Mount = "secret"
+	}
+	config.Mount = strings.Trim(config.Mount, "/")
+	config.Path = strings.Trim(config.Path, "/")
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &vaultProvider{config: config, client: client}, nil
+}
+
+// vaultKVResponse 为 GET /v1/{mount}/data/{path} 的响应，KV v2 在 data 外又包了一层 data
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+func (p *vaultProvider) GetSecret(c context.Context, name string) (string, error) {
+	data, err := p.read(c)
+	if err != nil {
+		return "", err
+	}
+	value, ok := data[name]
+	if !ok {
+		return "", domain.ErrSecretNotFound
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s is not a string", name)
+	}
+	return s, nil
+}
+
+func (p *vaultProvider) read(c context.Context) (map[string]any, error) {
+	endpoint := p.config.Address + "/v1/" + p.config.Mount + "/data/" + p.config.Path
+	req, err := http.NewRequestWithContext(c, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.config.Token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrSecretNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("vault GET %s: %s: %s", req.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var body vaultKVResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode vault response: %w", err)
+	}
+	return body.Data.Data, nil
+}
@@ -0,0 +1,96 @@
+// Package server 封装 http.Server，负责超时配置和收到停止信号后的优雅关闭
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type Config struct {
+	Addr         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// DrainPeriod 为就绪探针失败后继续接收请求的时间，留给负载均衡摘除实例
+	DrainPeriod time.Duration
+	// ShutdownTimeout 为等待处理中请求完成的最长时间
+	ShutdownTimeout time.Duration
+}
+
+type Server struct {
+	config Config
+	http   *http.Server
+	ready  atomic.Bool
+}
+
+func New(config Config, handler http.Handler) *Server {
+	return &Server{
+		config: config,
+		http: &http.Server{
+			Addr:              config.Addr,
+			Handler:           handler,
+			ReadTimeout:       config.ReadTimeout,
+			ReadHeaderTimeout: config.ReadTimeout,
+			WriteTimeout:      config.WriteTimeout,
+			IdleTimeout:       config.IdleTimeout,
+		},
+	}
+}
+
+// Ready 在服务开始监听后为 true，收到停止信号后立即变为 false
+func (s *Server) Ready() bool {
+	return s.ready.Load()
+}
+
+// ListenAndServe 监听 Config.Addr 并提供服务，直到 ctx 取消后完成优雅关闭
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.config.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve 在 ln 上提供服务。ctx 取消后依次：标记未就绪、等待 DrainPeriod、
+// 停止接收新连接并在 ShutdownTimeout 内等待处理中的请求完成
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.http.Serve(ln)
+	}()
+	s.ready.Store(true)
+	log.Info().Str("addr", ln.Addr().String()).Msg("HTTP 服务已启动")
+
+	select {
+	case err := <-serveErr:
+		s.ready.Store(false)
+		return err
+	case <-ctx.Done():
+	}
+
+	s.ready.Store(false)
+	log.Info().Dur("drain", s.config.DrainPeriod).Msg("收到停止信号，开始摘除流量")
+	select {
+	case <-time.After(s.config.DrainPeriod):
+	case err := <-serveErr:
+		return err
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.config.ShutdownTimeout)
+	defer cancel()
+	if err := s.http.Shutdown(shutdownCtx); err != nil {
+		_ = s.http.Close()
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Info().Msg("HTTP 服务已关闭")
+	return nil
+}
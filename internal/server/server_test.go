@@ -0,0 +1,107 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_InFlightRequestCompletesOnShutdown(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+
+	srv := New(Config{
+		DrainPeriod:     50 * time.Millisecond,
+		ShutdownTimeout: 5 * time.Second,
+	}, handler)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ctx, ln) }()
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, err := io.ReadAll(resp.Body)
+		response <- result{status: resp.StatusCode, body: string(body), err: err}
+	}()
+
+	<-started
+	assert.True(t, srv.Ready())
+	cancel()
+
+	assert.Eventually(t, func() bool { return !srv.Ready() }, time.Second, 5*time.Millisecond)
+	select {
+	case err := <-serveErr:
+		t.Fatalf("server stopped before in-flight request finished: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+
+	res := <-response
+	require.NoError(t, res.err)
+	assert.Equal(t, http.StatusOK, res.status)
+	assert.Equal(t, "done", res.body)
+	assert.NoError(t, <-serveErr)
+}
+
+func TestServer_NotReadyButServingDuringDrain(t *testing.T) {
+	srv := New(Config{
+		DrainPeriod:     300 * time.Millisecond,
+		ShutdownTimeout: time.Second,
+	}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	url := "http://" + ln.Addr().String() + "/"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ctx, ln) }()
+
+	assert.Eventually(t, srv.Ready, time.Second, 5*time.Millisecond)
+	assert.Equal(t, http.StatusOK, statusOf(t, url))
+
+	cancel()
+	assert.Eventually(t, func() bool { return !srv.Ready() }, time.Second, 5*time.Millisecond)
+	// 摘流期间已标记为未就绪，但仍然处理新请求
+	assert.Equal(t, http.StatusOK, statusOf(t, url))
+
+	assert.NoError(t, <-serveErr)
+}
+
+func statusOf(t *testing.T, url string) int {
+	t.Helper()
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
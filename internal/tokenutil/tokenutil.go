@@ -57,6 +57,14 @@ func IsAuthorized(requestToken string, secret string) (bool, error) {
 }
 
 func ExtractIDFromToken(requestToken string, secret string) (string, error) {
+	claims, err := ParseAccessToken(requestToken, secret)
+	if err != nil {
+		return "", err
+	}
+	return claims.ID, nil
+}
+
+func ParseAccessToken(requestToken string, secret string) (*domain.JwtCustomClaims, error) {
 	claims := &domain.JwtCustomClaims{}
 	token, err := jwt.ParseWithClaims(requestToken, claims, func(token *jwt.Token) (any, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -65,10 +73,10 @@ func ExtractIDFromToken(requestToken string, secret string) (string, error) {
 		return []byte(secret), nil
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if !token.Valid {
-		return "", fmt.Errorf("invalid token")
+		return nil, fmt.Errorf("invalid token")
 	}
-	return claims.ID, nil
+	return claims, nil
 }
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// expectedErrors 为业务上预期的失败，只记录为事件，不把 span 标记为错误
+var expectedErrors = []error{
+	domain.ErrInvalidCredentials,
+	domain.ErrUserNotFound,
+	domain.ErrUserAlreadyExists,
+	domain.ErrInvalidToken,
+	domain.ErrUserDisabled,
+	domain.ErrInvalidImage,
+	domain.ErrInvalidProfileAttributes,
+}
+
+// StartSpan 以 name 开启一个内部 span
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End 记录 err 并结束 span。业务预期内的错误不会把 span 状态置为 Error
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if !isExpected(err) {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
+
+func isExpected(err error) bool {
+	for _, target := range expectedErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
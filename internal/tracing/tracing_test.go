@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+	})
+	return exporter
+}
+
+type stubLogin struct {
+	err    error
+	gotCtx context.Context
+}
+
+func (s *stubLogin) Login(c context.Context, _, _ string) (domain.TokenPair, error) {
+	s.gotCtx = c
+	return domain.TokenPair{}, s.err
+}
+
+func TestTraceLogin(t *testing.T) {
+	t.Run("expected_error_is_not_span_error", func(t *testing.T) {
+		exporter := useInMemoryExporter(t)
+		stub := &stubLogin{err: domain.ErrInvalidCredentials}
+
+		_, err := TraceLogin(stub).Login(context.Background(), "a@example.com", "pw")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "LoginUsecase.Login", spans[0].Name)
+		assert.Equal(t, codes.Unset, spans[0].Status.Code)
+		assert.Len(t, spans[0].Events, 1)
+		// 被装饰的用例应收到携带新 span 的 ctx，以便仓储层 span 成为其子 span
+		assert.Equal(t, spans[0].SpanContext.SpanID(), trace.SpanContextFromContext(stub.gotCtx).SpanID())
+	})
+
+	t.Run("unexpected_error_marks_span", func(t *testing.T) {
+		exporter := useInMemoryExporter(t)
+
+		_, _ = TraceLogin(&stubLogin{err: errors.New("db down")}).Login(context.Background(), "a@example.com", "pw")
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+	})
+}
+
+func TestLogHook(t *testing.T) {
+	useInMemoryExporter(t)
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Hook(LogHook{})
+
+	ctx, span := StartSpan(context.Background(), "test")
+	logger.Info().Ctx(ctx).Msg("with span")
+	span.End()
+	withSpan := buf.String()
+	buf.Reset()
+	logger.Info().Msg("without span")
+
+	assert.Contains(t, withSpan, `"trace_id":"`+span.SpanContext().TraceID().String()+`"`)
+	assert.Contains(t, withSpan, `"span_id":"`+span.SpanContext().SpanID().String()+`"`)
+	assert.NotContains(t, buf.String(), "trace_id")
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: "zipkin"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const attrUserID = attribute.Key("enduser.id")
+
+type loginUsecase struct {
+	next domain.LoginUsecase
+}
+
+func TraceLogin(next domain.LoginUsecase) domain.LoginUsecase {
+	return &loginUsecase{next: next}
+}
+
+func (u *loginUsecase) Login(c context.Context, email, password string) (tokens domain.TokenPair, err error) {
+	ctx, span := StartSpan(c, "LoginUsecase.Login")
+	defer func() { End(span, err) }()
+	return u.next.Login(ctx, email, password)
+}
+
+type signupUsecase struct {
+	next domain.SignupUsecase
+}
+
+func TraceSignup(next domain.SignupUsecase) domain.SignupUsecase {
+	return &signupUsecase{next: next}
+}
+
+func (u *signupUsecase) Signup(c context.Context, name, email, password string) (tokens domain.TokenPair, err error) {
+	ctx, span := StartSpan(c, "SignupUsecase.Signup")
+	defer func() { End(span, err) }()
+	return u.next.Signup(ctx, name, email, password)
+}
+
+type refreshTokenUsecase struct {
+	next domain.RefreshTokenUsecase
+}
+
+func TraceRefreshToken(next domain.RefreshTokenUsecase) domain.RefreshTokenUsecase {
+	return &refreshTokenUsecase{next: next}
+}
+
+func (u *refreshTokenUsecase) Refresh(c context.Context, refreshToken string) (tokens domain.TokenPair, err error) {
+	ctx, span := StartSpan(c, "RefreshTokenUsecase.Refresh")
+	defer func() { End(span, err) }()
+	return u.next.Refresh(ctx, refreshToken)
+}
+
+type profileUsecase struct {
+	next domain.ProfileUsecase
+}
+
+func TraceProfile(next domain.ProfileUsecase) domain.ProfileUsecase {
+	return &profileUsecase{next: next}
+}
+
+func (u *profileUsecase) GetProfileByID(c context.Context, userID string) (profile *domain.Profile, err error) {
+	ctx, span := StartSpan(c, "ProfileUsecase.GetProfileByID", attrUserID.String(userID))
+	defer func() { End(span, err) }()
+	return u.next.GetProfileByID(ctx, userID)
+}
+
+func (u *profileUsecase) ChangePassword(c context.Context, userID string, oldPassword string, newPassword string) (err error) {
+	ctx, span := StartSpan(c, "ProfileUsecase.ChangePassword", attrUserID.String(userID))
+	defer func() { End(span, err) }()
+	return u.next.ChangePassword(ctx, userID, oldPassword, newPassword)
+}
+
+func (u *profileUsecase) DeleteAccount(c context.Context, userID string, password string) (err error) {
+	ctx, span := StartSpan(c, "ProfileUsecase.DeleteAccount", attrUserID.String(userID))
+	defer func() { End(span, err) }()
+	return u.next.DeleteAccount(ctx, userID, password)
+}
+
+func (u *profileUsecase) ExportData(c context.Context, userID string) (export *domain.UserDataExport, err error) {
+	ctx, span := StartSpan(c, "ProfileUsecase.ExportData", attrUserID.String(userID))
+	defer func() { End(span, err) }()
+	return u.next.ExportData(ctx, userID)
+}
+
+func (u *profileUsecase) UpdateProfile(c context.Context, userID string, update domain.ProfileUpdate) (profile *domain.Profile, err error) {
+	ctx, span := StartSpan(c, "ProfileUsecase.UpdateProfile", attrUserID.String(userID))
+	defer func() { End(span, err) }()
+	return u.next.UpdateProfile(ctx, userID, update)
+}
+
+func (u *profileUsecase) RequestEmailChange(c context.Context, userID string, password string, newEmail string) (err error) {
+	ctx, span := StartSpan(c, "ProfileUsecase.RequestEmailChange", attrUserID.String(userID))
+	defer func() { End(span, err) }()
+	return u.next.RequestEmailChange(ctx, userID, password, newEmail)
+}
+
+func (u *profileUsecase) ConfirmEmailChange(c context.Context, token string) (err error) {
+	ctx, span := StartSpan(c, "ProfileUsecase.ConfirmEmailChange")
+	defer func() { End(span, err) }()
+	return u.next.ConfirmEmailChange(ctx, token)
+}
+
+func (u *profileUsecase) UploadAvatar(c context.Context, userID string, image []byte) (profile *domain.Profile, err error) {
+	ctx, span := StartSpan(c, "ProfileUsecase.UploadAvatar",
+		attrUserID.String(userID),
+		attribute.Int("avatar.size_bytes", len(image)),
+	)
+	defer func() { End(span, err) }()
+	return u.next.UploadAvatar(ctx, userID, image)
+}
@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"context"
+	"sync"
+)
+
+// Group 管理一组后台 worker 的生命周期，Stop 取消全部 worker 并等待其退出
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewGroup(parent context.Context) *Group {
+	ctx, cancel := context.WithCancel(parent)
+	return &Group{ctx: ctx, cancel: cancel}
+}
+
+// Go 在新的 goroutine 中运行 fn，fn 应在 ctx 取消后尽快返回
+func (g *Group) Go(fn func(ctx context.Context)) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		fn(g.ctx)
+	}()
+}
+
+func (g *Group) Stop() {
+	g.cancel()
+	g.wg.Wait()
+}
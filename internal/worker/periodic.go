@@ -0,0 +1,27 @@
+// Package worker
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunPeriodic 每隔 interval 执行一次 fn，直到 ctx 被取消。fn 返回的错误只记录日志。
+func RunPeriodic(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info().Str("worker", name).Msg("worker stopped")
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Err(err).Str("worker", name).Msg("worker run failed")
+			}
+		}
+	}
+}
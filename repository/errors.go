@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/go-sql-driver/mysql"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// 各驱动表示超时的错误码
+var (
+	// query_canceled（statement_timeout）与 lock_not_available（lock_timeout）
+	postgresTimeoutCodes = map[string]bool{"57014": true, "55P03": true}
+	// ER_LOCK_WAIT_TIMEOUT 与 ER_QUERY_TIMEOUT（max_execution_time）
+	mysqlTimeoutCodes = map[uint16]bool{1205: true, 3024: true}
+)
+
+// SQLITE_BUSY、SQLITE_LOCKED：busy_timeout 内未拿到锁，扩展错误码的低 8 位为主错误码
+const (
+	sqliteBusy   = 5
+	sqliteLocked = 6
+)
+
+// userError 将数据库错误转换为用户相关的领域错误，无法识别的错误原样返回
+func userError(db *gorm.DB, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.ErrUserNotFound
+	case isDuplicatedKey(db, err):
+		return domain.ErrUserAlreadyExists
+	case isTimeout(err):
+		return fmt.Errorf("%w: %w", domain.ErrTimeout, err)
+	}
+	return err
+}
+
+// isDuplicatedKey 借助各方言自带的 ErrorTranslator 识别唯一约束冲突，
+// 无需依赖 gorm.Config.TranslateError 的全局设置
+func isDuplicatedKey(db *gorm.DB, err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	translator, ok := db.Dialector.(gorm.ErrorTranslator)
+	return ok && errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey)
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return postgresTimeoutCodes[pgErr.Code]
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlTimeoutCodes[mysqlErr.Number]
+	}
+	var sqliteErr *gosqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff
+		return code == sqliteBusy || code == sqliteLocked
+	}
+	return false
+}
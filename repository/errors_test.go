@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestUserError(t *testing.T) {
+	pg := &gorm.DB{Config: &gorm.Config{Dialector: postgres.Dialector{}}}
+	my := &gorm.DB{Config: &gorm.Config{Dialector: gormmysql.Dialector{}}}
+	cause := errors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		db       *gorm.DB
+		err      error
+		expected error
+	}{
+		{name: "nil", db: pg, err: nil, expected: nil},
+		{name: "not_found", db: pg, err: fmt.Errorf("query: %w", gorm.ErrRecordNotFound), expected: domain.ErrUserNotFound},
+		{name: "postgres_unique", db: pg, err: &pgconn.PgError{Code: "23505"}, expected: domain.ErrUserAlreadyExists},
+		{name: "mysql_unique", db: my, err: &mysql.MySQLError{Number: 1062}, expected: domain.ErrUserAlreadyExists},
+		{name: "deadline", db: pg, err: context.DeadlineExceeded, expected: domain.ErrTimeout},
+		{name: "postgres_statement_timeout", db: pg, err: &pgconn.PgError{Code: "57014"}, expected: domain.ErrTimeout},
+		{name: "mysql_lock_wait_timeout", db: my, err: &mysql.MySQLError{Number: 1205}, expected: domain.ErrTimeout},
+		{name: "other", db: pg, err: cause, expected: cause},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, userError(tt.db, tt.err), tt.expected)
+		})
+	}
+}
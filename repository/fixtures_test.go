@@ -0,0 +1,54 @@
+package repository_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dbtest"
+	"github.com/horaoen/go-backend-clean-architecture/repository/model"
+	"gorm.io/gorm"
+)
+
+// userFixtures 为常用测试数据：普通用户、已停用的管理员和一个一天前软删除的用户
+type userFixtures struct {
+	Alice, Bob, Carol model.UserModel
+}
+
+func loadUserFixtures(t *testing.T, db *gorm.DB) userFixtures {
+	t.Helper()
+	disabledAt := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	f := userFixtures{
+		Alice: model.UserModel{
+			Name:     "Alice",
+			Email:    "alice@example.com",
+			Password: "hashed",
+			Role:     domain.RoleUser,
+			Attributes: model.ProfileAttributes{
+				Locale:   "zh-CN",
+				Metadata: map[string]any{"plan": "pro"},
+			},
+		},
+		Bob: model.UserModel{
+			Name:       "Bob",
+			Email:      "bob@example.com",
+			Password:   "hashed",
+			Role:       domain.RoleAdmin,
+			DisabledAt: &disabledAt,
+		},
+		Carol: model.UserModel{
+			Name:      "Carol",
+			Email:     "carol@example.com",
+			Password:  "hashed",
+			Role:      domain.RoleUser,
+			DeletedAt: gorm.DeletedAt{Time: time.Now().Add(-24 * time.Hour), Valid: true},
+		},
+	}
+	dbtest.Insert(t, db, &f.Alice, &f.Bob, &f.Carol)
+	return f
+}
+
+func idOf(m model.UserModel) string {
+	return strconv.FormatUint(uint64(m.ID), 10)
+}
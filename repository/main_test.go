@@ -0,0 +1,12 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/internal/dbtest"
+)
+
+// 默认使用内嵌 Postgres，不可用时退回 SQLite；TEST_DB_DRIVER/TEST_DB_DSN 可指定 SQLite 或外部 Postgres、MySQL，见 dbtest 包说明
+func TestMain(m *testing.M) {
+	dbtest.Main(m)
+}
@@ -0,0 +1,8 @@
+// Package migrations 按数据库方言存放版本化 SQL 迁移文件，
+// 文件名格式为 <版本号>_<名称>.up.sql / <版本号>_<名称>.down.sql
+package migrations
+
+import "embed"
+
+//go:embed postgres/*.sql mysql/*.sql sqlite/*.sql
+var FS embed.FS
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP INDEX idx_users_email,
+    DROP INDEX idx_users_active_email,
+    DROP COLUMN active_email,
+    ADD UNIQUE INDEX idx_users_email (email);
@@ -0,0 +1,7 @@
+-- MySQL 不支持部分索引：用生成列只为未删除的账号保留邮箱，唯一索引会忽略其中的 NULL，
+-- 软删除后保留期内同一邮箱可以重新注册
+ALTER TABLE users
+    ADD COLUMN active_email VARCHAR(255) GENERATED ALWAYS AS (IF(deleted_at IS NULL, email, NULL)) STORED,
+    DROP INDEX idx_users_email,
+    ADD UNIQUE INDEX idx_users_active_email (active_email),
+    ADD INDEX idx_users_email (email);
@@ -0,0 +1,3 @@
+-- 邮箱唯一性只约束未删除的账号，软删除后保留期内同一邮箱可以重新注册
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email) WHERE deleted_at IS NULL;
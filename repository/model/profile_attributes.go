@@ -0,0 +1,79 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ProfileAttributes 以 JSON 文档形式存储用户扩展属性，Postgres 下使用 jsonb 列
+type ProfileAttributes struct {
+	DisplayName string         `json:"displayName,omitempty"`
+	Locale      string         `json:"locale,omitempty"`
+	Timezone    string         `json:"timezone,omitempty"`
+	Phone       string         `json:"phone,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+func (a ProfileAttributes) Value() (driver.Value, error) {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (a *ProfileAttributes) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*a = ProfileAttributes{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported profile attributes type: %T", value)
+	}
+	*a = ProfileAttributes{}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, a)
+}
+
+func (ProfileAttributes) GormDBDataType(db *gorm.DB, field *schema.Field) string {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return "jsonb"
+	case "mysql":
+		return "json"
+	default:
+		return "text"
+	}
+}
+
+func (a ProfileAttributes) ToDomain() domain.ProfileAttributes {
+	return domain.ProfileAttributes{
+		DisplayName: a.DisplayName,
+		Locale:      a.Locale,
+		Timezone:    a.Timezone,
+		Phone:       a.Phone,
+		Metadata:    a.Metadata,
+	}
+}
+
+func ToProfileAttributes(a domain.ProfileAttributes) ProfileAttributes {
+	return ProfileAttributes{
+		DisplayName: a.DisplayName,
+		Locale:      a.Locale,
+		Timezone:    a.Timezone,
+		Phone:       a.Phone,
+		Metadata:    a.Metadata,
+	}
+}
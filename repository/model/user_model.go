@@ -4,15 +4,24 @@ import (
 	"time"
 
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"gorm.io/gorm"
 )
 
 type UserModel struct {
-	ID        uint   `gorm:"primaryKey"`
-	Name      string `gorm:"size:255;not null"`
-	Email     string `gorm:"size:255;uniqueIndex;not null"`
-	Password  string `gorm:"column:password;size:255;not null"`
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"size:255;not null"`
+	// 唯一索引只覆盖未删除的账号；MySQL 的 AutoMigrate 不支持 where，需通过 migrate 子命令建表
+	Email      string            `gorm:"size:255;uniqueIndex:idx_users_email,where:deleted_at IS NULL;not null"`
+	Password   string            `gorm:"column:password;size:255;not null"`
+	Avatar     string            `gorm:"size:255;not null;default:''"`
+	Attributes ProfileAttributes `gorm:"not null;default:'{}'"`
+	Role       string            `gorm:"size:32;not null;default:'user'"`
+	DisabledAt *time.Time
+	// AnonymizedAt 非空表示账号已过保留期，个人数据已被匿名化
+	AnonymizedAt *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
 }
 
 func (UserModel) TableName() string {
@@ -21,22 +30,30 @@ func (UserModel) TableName() string {
 
 func (m *UserModel) ToDomain() domain.User {
 	return domain.User{
-		ID:        m.ID,
-		Name:      m.Name,
-		Email:     m.Email,
-		Password:  m.Password,
-		CreatedAt: m.CreatedAt,
-		UpdatedAt: m.UpdatedAt,
+		ID:         m.ID,
+		Name:       m.Name,
+		Email:      m.Email,
+		Password:   m.Password,
+		Avatar:     m.Avatar,
+		Attributes: m.Attributes.ToDomain(),
+		Role:       m.Role,
+		DisabledAt: m.DisabledAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
 	}
 }
 
 func ToUserModel(u *domain.User) UserModel {
 	return UserModel{
-		ID:        u.ID,
-		Name:      u.Name,
-		Email:     u.Email,
-		Password:  u.Password,
-		CreatedAt: u.CreatedAt,
-		UpdatedAt: u.UpdatedAt,
+		ID:         u.ID,
+		Name:       u.Name,
+		Email:      u.Email,
+		Password:   u.Password,
+		Avatar:     u.Avatar,
+		Attributes: ToProfileAttributes(u.Attributes),
+		Role:       u.Role,
+		DisabledAt: u.DisabledAt,
+		CreatedAt:  u.CreatedAt,
+		UpdatedAt:  u.UpdatedAt,
 	}
 }
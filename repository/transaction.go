@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type txManager struct {
+	db *gorm.DB
+}
+
+func NewTxManager(db *gorm.DB) domain.TxManager {
+	return &txManager{
+		db: db,
+	}
+}
+
+func (tm *txManager) WithinTransaction(c context.Context, fn func(c context.Context) error) error {
+	return conn(c, tm.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(c, txKey{}, tx))
+	})
+}
+
+// conn 返回 context 中携带的事务，没有事务时返回 db；仓储方法都应通过它访问数据库
+func conn(c context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := c.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(c)
+	}
+	return db.WithContext(c)
+}
@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/horaoen/go-backend-clean-architecture/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxManager_Commit(t *testing.T) {
+	db := newTestDB(t)
+	repo := repository.NewUserRepository(db)
+	tm := repository.NewTxManager(db)
+
+	err := tm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		user := createUserCtx(t, ctx, repo, "a@example.com")
+		user.Name = "Renamed"
+		return repo.Update(ctx, &user)
+	})
+	require.NoError(t, err)
+
+	user, err := repo.GetByEmail(context.Background(), "a@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", user.Name)
+}
+
+func TestTxManager_RollbackOnError(t *testing.T) {
+	db := newTestDB(t)
+	repo := repository.NewUserRepository(db)
+	tm := repository.NewTxManager(db)
+	errFailed := errors.New("audit write failed")
+
+	err := tm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		createUserCtx(t, ctx, repo, "a@example.com")
+		// 事务内可以读到尚未提交的数据
+		_, err := repo.GetByEmail(ctx, "a@example.com")
+		require.NoError(t, err)
+		return errFailed
+	})
+	assert.ErrorIs(t, err, errFailed)
+
+	_, err = repo.GetByEmail(context.Background(), "a@example.com")
+	assert.Error(t, err)
+}
+
+func TestTxManager_RollbackOnPanic(t *testing.T) {
+	db := newTestDB(t)
+	repo := repository.NewUserRepository(db)
+	tm := repository.NewTxManager(db)
+
+	assert.Panics(t, func() {
+		_ = tm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+			createUserCtx(t, ctx, repo, "a@example.com")
+			panic("boom")
+		})
+	})
+
+	_, err := repo.GetByEmail(context.Background(), "a@example.com")
+	assert.Error(t, err)
+}
+
+func TestTxManager_NestedRollsBackInnerOnly(t *testing.T) {
+	db := newTestDB(t)
+	repo := repository.NewUserRepository(db)
+	tm := repository.NewTxManager(db)
+
+	err := tm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		createUserCtx(t, ctx, repo, "outer@example.com")
+		innerErr := tm.WithinTransaction(ctx, func(ctx context.Context) error {
+			createUserCtx(t, ctx, repo, "inner@example.com")
+			return errors.New("inner failed")
+		})
+		assert.Error(t, innerErr)
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = repo.GetByEmail(context.Background(), "outer@example.com")
+	assert.NoError(t, err)
+	_, err = repo.GetByEmail(context.Background(), "inner@example.com")
+	assert.Error(t, err)
+}
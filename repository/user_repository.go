@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/horaoen/go-backend-clean-architecture/domain"
 	"github.com/horaoen/go-backend-clean-architecture/repository/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
 )
 
 type userRepository struct {
@@ -21,8 +25,8 @@ func NewUserRepository(db *gorm.DB) domain.UserRepository {
 
 func (ur *userRepository) Create(c context.Context, user *domain.User) error {
 	userModel := model.ToUserModel(user)
-	if err := ur.db.WithContext(c).Create(&userModel).Error; err != nil {
-		return err
+	if err := conn(c, ur.db).Create(&userModel).Error; err != nil {
+		return userError(ur.db, err)
 	}
 	user.ID = userModel.ID
 	user.CreatedAt = userModel.CreatedAt
@@ -30,11 +34,12 @@ func (ur *userRepository) Create(c context.Context, user *domain.User) error {
 	return nil
 }
 
+// Fetch 在配置了只读副本时从副本读取
 func (ur *userRepository) Fetch(c context.Context) ([]domain.User, error) {
 	var userModels []model.UserModel
-	err := ur.db.WithContext(c).Select("id", "name", "email", "created_at", "updated_at").Find(&userModels).Error
+	err := conn(c, ur.db).Select("id", "name", "email", "created_at", "updated_at").Find(&userModels).Error
 	if err != nil {
-		return nil, err
+		return nil, userError(ur.db, err)
 	}
 
 	if userModels == nil {
@@ -49,35 +54,141 @@ func (ur *userRepository) Fetch(c context.Context) ([]domain.User, error) {
 	return users, nil
 }
 
+// GetByEmail 始终读主库：登录、注册查重等场景需要看到刚写入的数据，不能容忍副本延迟
 func (ur *userRepository) GetByEmail(c context.Context, email string) (domain.User, error) {
+	return ur.getByEmail(c, email)
+}
+
+// GetByEmailForUpdate 对读到的记录加行锁，直到所在事务结束
+func (ur *userRepository) GetByEmailForUpdate(c context.Context, email string) (domain.User, error) {
+	return ur.getByEmail(c, email, clause.Locking{Strength: clause.LockingStrengthUpdate})
+}
+
+// GetByID 在配置了只读副本时从副本读取，结果可能略有延迟；需要修改后写回时使用 GetByIDForUpdate
+func (ur *userRepository) GetByID(c context.Context, id string) (domain.User, error) {
+	return ur.getByID(c, id)
+}
+
+// GetByIDForUpdate 从主库读取并对记录加行锁，直到所在事务结束
+func (ur *userRepository) GetByIDForUpdate(c context.Context, id string) (domain.User, error) {
+	return ur.getByID(c, id, dbresolver.Write, clause.Locking{Strength: clause.LockingStrengthUpdate})
+}
+
+func (ur *userRepository) getByEmail(c context.Context, email string, clauses ...clause.Expression) (domain.User, error) {
 	var userModel model.UserModel
-	err := ur.db.WithContext(c).Where("email = ?", email).First(&userModel).Error
+	err := conn(c, ur.db).Clauses(dbresolver.Write).Clauses(clauses...).Where("email = ?", email).First(&userModel).Error
 	if err != nil {
-		return domain.User{}, err
+		return domain.User{}, userError(ur.db, err)
 	}
 	return userModel.ToDomain(), nil
 }
 
-func (ur *userRepository) GetByID(c context.Context, id string) (domain.User, error) {
+func (ur *userRepository) getByID(c context.Context, id string, clauses ...clause.Expression) (domain.User, error) {
 	var userModel model.UserModel
 
+	// 非数字 id 不可能对应任何用户
 	userID, err := strconv.ParseUint(id, 10, 64)
 	if err != nil {
-		return domain.User{}, err
+		return domain.User{}, domain.ErrUserNotFound
 	}
 
-	err = ur.db.WithContext(c).First(&userModel, userID).Error
+	err = conn(c, ur.db).Clauses(clauses...).First(&userModel, userID).Error
 	if err != nil {
-		return domain.User{}, err
+		return domain.User{}, userError(ur.db, err)
 	}
 	return userModel.ToDomain(), nil
 }
 
 func (ur *userRepository) Update(c context.Context, user *domain.User) error {
 	userModel := model.ToUserModel(user)
-	if err := ur.db.WithContext(c).Save(&userModel).Error; err != nil {
-		return err
+	// 不使用 Save：记录不存在（包括已软删除）时 Save 会退化为插入，把已删除的用户写回来
+	result := conn(c, ur.db).Select("*").Omit("created_at", "deleted_at", "anonymized_at").Updates(&userModel)
+	if result.Error != nil {
+		return userError(ur.db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
 	}
 	user.UpdatedAt = userModel.UpdatedAt
 	return nil
 }
+
+func (ur *userRepository) Delete(c context.Context, id string) error {
+	userID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	result := conn(c, ur.db).Delete(&model.UserModel{}, userID)
+	if result.Error != nil {
+		return userError(ur.db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// FetchPurgeable 从主库读取：结果会立即用于删除或匿名化
+func (ur *userRepository) FetchPurgeable(c context.Context, before time.Time, limit int) ([]domain.User, error) {
+	var userModels []model.UserModel
+	err := conn(c, ur.db).Clauses(dbresolver.Write).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND anonymized_at IS NULL", before).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&userModels).Error
+	if err != nil {
+		return nil, userError(ur.db, err)
+	}
+
+	users := make([]domain.User, len(userModels))
+	for i, m := range userModels {
+		users[i] = m.ToDomain()
+	}
+	return users, nil
+}
+
+func (ur *userRepository) Purge(c context.Context, id string) error {
+	userID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	// 只允许删除已软删除的记录，避免误删活跃账号
+	result := conn(c, ur.db).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Delete(&model.UserModel{}, userID)
+	if result.Error != nil {
+		return userError(ur.db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (ur *userRepository) Anonymize(c context.Context, id string) error {
+	userID, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return domain.ErrUserNotFound
+	}
+
+	// 邮箱替换为按 id 生成的不可投递地址，保持非空且互不相同
+	result := conn(c, ur.db).Unscoped().Model(&model.UserModel{}).
+		Where("id = ? AND deleted_at IS NOT NULL", userID).
+		Updates(map[string]any{
+			"name":          "",
+			"email":         fmt.Sprintf("deleted-%d@anonymized.invalid", userID),
+			"password":      "",
+			"avatar":        "",
+			"attributes":    model.ProfileAttributes{},
+			"anonymized_at": time.Now(),
+		})
+	if result.Error != nil {
+		return userError(ur.db, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
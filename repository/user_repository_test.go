@@ -0,0 +1,391 @@
+package repository_test
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/internal/database"
+	"github.com/horaoen/go-backend-clean-architecture/internal/dbtest"
+	"github.com/horaoen/go-backend-clean-architecture/repository"
+	"github.com/horaoen/go-backend-clean-architecture/repository/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB 返回在独立事务中的测试数据库，测试结束时回滚
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	return dbtest.DB(t)
+}
+
+func createUser(t *testing.T, repo domain.UserRepository, email string) domain.User {
+	t.Helper()
+	return createUserCtx(t, context.Background(), repo, email)
+}
+
+func createUserCtx(t *testing.T, ctx context.Context, repo domain.UserRepository, email string) domain.User {
+	t.Helper()
+	user := domain.User{
+		Name:     "Test",
+		Email:    email,
+		Password: "hashed",
+		Role:     domain.RoleUser,
+		Attributes: domain.ProfileAttributes{
+			Locale:   "zh-CN",
+			Metadata: map[string]any{"plan": "pro"},
+		},
+	}
+	require.NoError(t, repo.Create(ctx, &user))
+	return user
+}
+
+func TestUserRepository_Create(t *testing.T) {
+	repo := repository.NewUserRepository(newTestDB(t))
+	ctx := context.Background()
+
+	user := createUser(t, repo, "a@example.com")
+	assert.NotZero(t, user.ID)
+	assert.False(t, user.CreatedAt.IsZero())
+	assert.False(t, user.UpdatedAt.IsZero())
+
+	got, err := repo.GetByEmail(ctx, "a@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID)
+	assert.Equal(t, "hashed", got.Password)
+	assert.Equal(t, domain.RoleUser, got.Role)
+	assert.Equal(t, "zh-CN", got.Attributes.Locale)
+	assert.Equal(t, "pro", got.Attributes.Metadata["plan"])
+}
+
+func TestUserRepository_Create_DuplicateEmail(t *testing.T) {
+	db := newTestDB(t)
+	loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+
+	user := domain.User{Name: "Dup", Email: "alice@example.com", Password: "hashed", Role: domain.RoleUser}
+	assert.ErrorIs(t, repo.Create(context.Background(), &user), domain.ErrUserAlreadyExists)
+	assert.Zero(t, user.ID)
+}
+
+func TestUserRepository_Create_ReuseDeletedEmail(t *testing.T) {
+	// 唯一索引只覆盖未删除的账号，已软删除账号的邮箱在保留期内即可重新注册
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	user := createUser(t, repo, "carol@example.com")
+	assert.NotEqual(t, f.Carol.ID, user.ID)
+
+	got, err := repo.GetByEmail(ctx, "carol@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, got.ID, "resolves to the active account")
+
+	dup := domain.User{Name: "Dup", Email: "carol@example.com", Password: "hashed", Role: domain.RoleUser}
+	assert.ErrorIs(t, repo.Create(ctx, &dup), domain.ErrUserAlreadyExists, "still unique among active accounts")
+}
+
+func TestUserRepository_Fetch(t *testing.T) {
+	db := newTestDB(t)
+	repo := repository.NewUserRepository(db)
+
+	users, err := repo.Fetch(context.Background())
+	require.NoError(t, err)
+	assert.NotNil(t, users)
+	assert.Empty(t, users)
+
+	loadUserFixtures(t, db)
+	users, err = repo.Fetch(context.Background())
+	require.NoError(t, err)
+	emails := make([]string, len(users))
+	for i, u := range users {
+		emails[i] = u.Email
+		assert.Empty(t, u.Password, "Fetch does not load password hashes")
+		assert.False(t, u.CreatedAt.IsZero())
+	}
+	assert.ElementsMatch(t, []string{"alice@example.com", "bob@example.com"}, emails)
+}
+
+func TestUserRepository_GetByEmail(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	got, err := repo.GetByEmail(ctx, "bob@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, f.Bob.ID, got.ID)
+	assert.Equal(t, domain.RoleAdmin, got.Role)
+	require.NotNil(t, got.DisabledAt)
+	assert.True(t, f.Bob.DisabledAt.Equal(*got.DisabledAt))
+
+	_, err = repo.GetByEmail(ctx, "nobody@example.com")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	_, err = repo.GetByEmail(ctx, "carol@example.com")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound, "soft-deleted user is hidden")
+}
+
+func TestUserRepository_GetByID(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	got, err := repo.GetByID(ctx, idOf(f.Alice))
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", got.Email)
+	assert.Equal(t, "Alice", got.Name)
+
+	_, err = repo.GetByID(ctx, "not-a-number")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	_, err = repo.GetByID(ctx, strconv.FormatUint(uint64(f.Carol.ID+1000), 10))
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	_, err = repo.GetByID(ctx, idOf(f.Carol))
+	assert.ErrorIs(t, err, domain.ErrUserNotFound, "soft-deleted user is hidden")
+}
+
+func TestUserRepository_GetForUpdate(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	tm := repository.NewTxManager(db)
+
+	err := tm.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		byID, err := repo.GetByIDForUpdate(ctx, idOf(f.Alice))
+		require.NoError(t, err)
+		assert.Equal(t, "alice@example.com", byID.Email)
+
+		byEmail, err := repo.GetByEmailForUpdate(ctx, "alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, byID.ID, byEmail.ID)
+
+		_, err = repo.GetByIDForUpdate(ctx, idOf(f.Carol))
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, "soft-deleted user is hidden")
+		_, err = repo.GetByEmailForUpdate(ctx, "missing@example.com")
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestUserRepository_Update(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	user, err := repo.GetByID(ctx, idOf(f.Alice))
+	require.NoError(t, err)
+	disabledAt := time.Now().UTC().Truncate(time.Second)
+	user.Name = "Renamed"
+	user.Role = domain.RoleAdmin
+	user.DisabledAt = &disabledAt
+	user.Attributes.Timezone = "Asia/Shanghai"
+	require.NoError(t, repo.Update(ctx, &user))
+
+	got, err := repo.GetByEmail(ctx, "alice@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", got.Name)
+	assert.Equal(t, domain.RoleAdmin, got.Role)
+	assert.Equal(t, "Asia/Shanghai", got.Attributes.Timezone)
+	assert.Equal(t, "zh-CN", got.Attributes.Locale)
+	require.NotNil(t, got.DisabledAt)
+	assert.True(t, disabledAt.Equal(*got.DisabledAt))
+	assert.True(t, got.CreatedAt.Equal(user.CreatedAt))
+}
+
+func TestUserRepository_Update_DuplicateEmail(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+
+	user := f.Alice.ToDomain()
+	user.Email = "bob@example.com"
+	assert.ErrorIs(t, repo.Update(context.Background(), &user), domain.ErrUserAlreadyExists)
+}
+
+func TestUserRepository_Update_Deleted(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+
+	user := f.Carol.ToDomain()
+	user.Name = "Revived"
+	assert.ErrorIs(t, repo.Update(context.Background(), &user), domain.ErrUserNotFound)
+
+	var stored model.UserModel
+	require.NoError(t, db.Unscoped().First(&stored, f.Carol.ID).Error)
+	assert.Equal(t, "Carol", stored.Name)
+	assert.True(t, stored.DeletedAt.Valid, "update must not restore a deleted user")
+}
+
+func TestUserRepository_Delete(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Delete(ctx, idOf(f.Alice)))
+
+	_, err := repo.GetByID(ctx, idOf(f.Alice))
+	assert.ErrorIs(t, err, domain.ErrUserNotFound, "soft-deleted user is hidden")
+
+	var stored model.UserModel
+	require.NoError(t, db.Unscoped().First(&stored, f.Alice.ID).Error)
+	assert.True(t, stored.DeletedAt.Valid, "row is kept until purged")
+
+	assert.ErrorIs(t, repo.Delete(ctx, idOf(f.Alice)), domain.ErrUserNotFound, "already deleted")
+	assert.ErrorIs(t, repo.Delete(ctx, "not-a-number"), domain.ErrUserNotFound)
+}
+
+func TestUserRepository_Timeout(t *testing.T) {
+	repo := repository.NewUserRepository(newTestDB(t))
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	_, err := repo.GetByEmail(ctx, "alice@example.com")
+	assert.ErrorIs(t, err, domain.ErrTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "keeps the cause")
+}
+
+func TestUserRepository_FetchPurgeable(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	users, err := repo.FetchPurgeable(ctx, time.Now().Add(-48*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, users, "deleted within retention period")
+
+	users, err = repo.FetchPurgeable(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	require.Len(t, users, 1, "active users are never purgeable")
+	assert.Equal(t, f.Carol.ID, users[0].ID)
+
+	require.NoError(t, repo.Anonymize(ctx, idOf(f.Carol)))
+	users, err = repo.FetchPurgeable(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, users, "anonymized accounts are skipped")
+}
+
+func TestUserRepository_Purge(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Purge(ctx, idOf(f.Carol)))
+
+	var count int64
+	require.NoError(t, db.Unscoped().Model(&model.UserModel{}).Where("id = ?", f.Carol.ID).Count(&count).Error)
+	assert.Zero(t, count)
+
+	assert.ErrorIs(t, repo.Purge(ctx, idOf(f.Alice)), domain.ErrUserNotFound, "active users are never purged")
+	_, err := repo.GetByID(ctx, idOf(f.Alice))
+	assert.NoError(t, err)
+}
+
+func TestUserRepository_Anonymize(t *testing.T) {
+	db := newTestDB(t)
+	f := loadUserFixtures(t, db)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Anonymize(ctx, idOf(f.Carol)))
+
+	var got model.UserModel
+	require.NoError(t, db.Unscoped().First(&got, f.Carol.ID).Error)
+	assert.Empty(t, got.Name)
+	assert.Empty(t, got.Password)
+	assert.Empty(t, got.Avatar)
+	assert.Equal(t, model.ProfileAttributes{}, got.Attributes)
+	assert.Equal(t, "deleted-"+idOf(f.Carol)+"@anonymized.invalid", got.Email)
+	assert.NotNil(t, got.AnonymizedAt)
+	assert.True(t, got.DeletedAt.Valid, "stays soft deleted")
+
+	assert.ErrorIs(t, repo.Anonymize(ctx, idOf(f.Alice)), domain.ErrUserNotFound, "active users are never anonymized")
+}
+
+// 读写分离需要两个独立的数据库，这里固定使用 SQLite 文件
+func TestUserRepository_ReadReplica(t *testing.T) {
+	primary := openSQLite(t, "primary.db")
+	replica := openSQLite(t, "replica.db")
+	db, err := database.Open(primary.Dialector, []gorm.Dialector{replica.Dialector}, database.PoolConfig{}, &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	// 写入只到达主库，副本尚未同步
+	user := createUser(t, repo, "a@example.com")
+
+	_, err = repo.GetByEmail(ctx, "a@example.com")
+	assert.NoError(t, err, "GetByEmail should read the primary")
+
+	id := strconv.FormatUint(uint64(user.ID), 10)
+	_, err = repo.GetByID(ctx, id)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound, "GetByID should read the replica")
+	_, err = repo.GetByIDForUpdate(ctx, id)
+	assert.NoError(t, err, "GetByIDForUpdate should read the primary")
+	users, err := repo.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	createUser(t, repository.NewUserRepository(replica), "a@example.com")
+	users, err = repo.Fetch(ctx)
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+}
+
+func TestUserRepository_ReadReplica_ReadModifyWrite(t *testing.T) {
+	primary := openSQLite(t, "primary.db")
+	replica := openSQLite(t, "replica.db")
+	db, err := database.Open(primary.Dialector, []gorm.Dialector{replica.Dialector}, database.PoolConfig{}, &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	// 副本停留在旧密码，主库已更新
+	createUser(t, repository.NewUserRepository(replica), "a@example.com")
+	user := createUser(t, repo, "a@example.com")
+	user.Password = "new-hash"
+	require.NoError(t, repo.Update(ctx, &user))
+
+	id := strconv.FormatUint(uint64(user.ID), 10)
+	loaded, err := repo.GetByIDForUpdate(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "new-hash", loaded.Password)
+
+	loaded.Name = "Renamed"
+	require.NoError(t, repo.Update(ctx, &loaded))
+
+	var stored model.UserModel
+	require.NoError(t, primary.First(&stored, user.ID).Error)
+	assert.Equal(t, "Renamed", stored.Name)
+	assert.Equal(t, "new-hash", stored.Password, "update must not revert fields to replica values")
+}
+
+func openSQLite(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	dialector, err := database.Dialector(database.Config{
+		Driver: database.DriverSQLite,
+		Name:   filepath.Join(t.TempDir(), name),
+	})
+	require.NoError(t, err)
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, dbtest.Migrate(db))
+	return db
+}
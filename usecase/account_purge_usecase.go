@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// purgeBatchSize 为单次清理处理的账号上限，剩余账号留给下一轮
+const purgeBatchSize = 100
+
+type accountPurgeUsecase struct {
+	userRepository domain.UserRepository
+	blobStore      domain.BlobStore
+	retention      time.Duration
+	mode           string
+	contextTimeout time.Duration
+}
+
+// NewAccountPurgeUsecase 创建账号清理用例，mode 为 domain.PurgeModeDelete 或 domain.PurgeModeAnonymize
+func NewAccountPurgeUsecase(userRepository domain.UserRepository, blobStore domain.BlobStore, retention time.Duration, mode string, timeout time.Duration) domain.AccountPurgeUsecase {
+	return &accountPurgeUsecase{
+		userRepository: userRepository,
+		blobStore:      blobStore,
+		retention:      retention,
+		mode:           mode,
+		contextTimeout: timeout,
+	}
+}
+
+func (apu *accountPurgeUsecase) PurgeDeleted(c context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(c, apu.contextTimeout)
+	defer cancel()
+
+	users, err := apu.userRepository.FetchPurgeable(ctx, time.Now().Add(-apu.retention), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var purged int64
+	var skipped []error
+	for _, user := range users {
+		id := strconv.FormatUint(uint64(user.ID), 10)
+		// 先删头像再处理记录：头像删除失败时保留记录留待下一轮重试，并继续处理其余账号，
+		// 避免个别无法删除的文件阻塞之后所有账号的清理
+		if err := apu.deleteAvatar(ctx, user.Avatar); err != nil {
+			log.Ctx(ctx).Err(err).Str("userId", id).Msg("删除账号头像失败，跳过该账号")
+			skipped = append(skipped, err)
+			continue
+		}
+
+		if apu.mode == domain.PurgeModeAnonymize {
+			err = apu.userRepository.Anonymize(ctx, id)
+		} else {
+			err = apu.userRepository.Purge(ctx, id)
+		}
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, errors.Join(skipped...)
+}
+
+func (apu *accountPurgeUsecase) deleteAvatar(ctx context.Context, prefix string) error {
+	if prefix == "" {
+		return nil
+	}
+	for _, size := range AvatarSizes {
+		if err := apu.blobStore.Delete(ctx, avatarKey(prefix, size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
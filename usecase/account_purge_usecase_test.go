@@ -0,0 +1,90 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccountPurgeUsecase_PurgeDeleted(t *testing.T) {
+	retention := 24 * time.Hour
+	matchCutoff := mock.MatchedBy(func(before time.Time) bool {
+		cutoff := time.Now().Add(-retention)
+		return before.Before(cutoff.Add(time.Second)) && before.After(cutoff.Add(-time.Minute))
+	})
+	deleted := []domain.User{
+		{ID: 1, Avatar: "avatars/1/abc"},
+		{ID: 2},
+	}
+
+	t.Run("delete", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockBlobStore := new(MockBlobStore)
+		mockRepo.On("FetchPurgeable", mock.Anything, matchCutoff, mock.AnythingOfType("int")).Return(deleted, nil)
+		for _, size := range usecase.AvatarSizes {
+			mockBlobStore.On("Delete", mock.Anything, fmt.Sprintf("avatars/1/abc/%d.png", size)).Return(nil).Once()
+		}
+		mockRepo.On("Purge", mock.Anything, "1").Return(nil).Once()
+		mockRepo.On("Purge", mock.Anything, "2").Return(nil).Once()
+
+		u := usecase.NewAccountPurgeUsecase(mockRepo, mockBlobStore, retention, domain.PurgeModeDelete, time.Second*2)
+		purged, err := u.PurgeDeleted(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+		mockRepo.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("anonymize", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockBlobStore := new(MockBlobStore)
+		mockRepo.On("FetchPurgeable", mock.Anything, matchCutoff, mock.AnythingOfType("int")).Return(deleted, nil)
+		mockBlobStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+		mockRepo.On("Anonymize", mock.Anything, "1").Return(nil).Once()
+		mockRepo.On("Anonymize", mock.Anything, "2").Return(nil).Once()
+
+		u := usecase.NewAccountPurgeUsecase(mockRepo, mockBlobStore, retention, domain.PurgeModeAnonymize, time.Second*2)
+		purged, err := u.PurgeDeleted(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+	})
+
+	t.Run("avatar_delete_failed", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockBlobStore := new(MockBlobStore)
+		users := []domain.User{
+			{ID: 1, Avatar: "avatars/1/abc"},
+			{ID: 2},
+			{ID: 3, Avatar: "avatars/3/def"},
+		}
+		mockRepo.On("FetchPurgeable", mock.Anything, matchCutoff, mock.AnythingOfType("int")).Return(users, nil)
+		mockBlobStore.On("Delete", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "avatars/1/")
+		})).Return(errors.New("forbidden"))
+		mockBlobStore.On("Delete", mock.Anything, mock.MatchedBy(func(key string) bool {
+			return strings.HasPrefix(key, "avatars/3/")
+		})).Return(nil)
+		mockRepo.On("Purge", mock.Anything, "2").Return(nil).Once()
+		mockRepo.On("Purge", mock.Anything, "3").Return(nil).Once()
+
+		u := usecase.NewAccountPurgeUsecase(mockRepo, mockBlobStore, retention, domain.PurgeModeDelete, time.Second*2)
+		purged, err := u.PurgeDeleted(context.Background())
+
+		assert.Error(t, err, "the skipped account is still reported")
+		assert.Equal(t, int64(2), purged, "later accounts are purged past the failing one")
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Purge", mock.Anything, "1")
+	})
+}
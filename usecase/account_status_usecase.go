@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+)
+
+type accountStatusUsecase struct {
+	userRepository domain.UserRepository
+	contextTimeout time.Duration
+}
+
+func NewAccountStatusUsecase(userRepository domain.UserRepository, timeout time.Duration) domain.AccountStatusUsecase {
+	return &accountStatusUsecase{
+		userRepository: userRepository,
+		contextTimeout: timeout,
+	}
+}
+
+func (asu *accountStatusUsecase) CheckActive(c context.Context, userID string) (string, error) {
+	ctx, cancel := context.WithTimeout(c, asu.contextTimeout)
+	defer cancel()
+
+	user, err := asu.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return "", domain.ErrUnauthorized
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if user.DisabledAt != nil {
+		return "", domain.ErrUserDisabled
+	}
+	return user.Role, nil
+}
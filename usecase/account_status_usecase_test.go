@@ -0,0 +1,47 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccountStatusUsecase_CheckActive(t *testing.T) {
+	disabledAt := time.Now()
+
+	tests := []struct {
+		name     string
+		user     domain.User
+		repoErr  error
+		wantRole string
+		wantErr  error
+	}{
+		{name: "active", user: domain.User{ID: 1, Role: domain.RoleAdmin}, wantRole: domain.RoleAdmin},
+		{name: "demoted", user: domain.User{ID: 1, Role: domain.RoleUser}, wantRole: domain.RoleUser},
+		{name: "disabled", user: domain.User{ID: 1, DisabledAt: &disabledAt}, wantErr: domain.ErrUserDisabled},
+		{name: "deleted", repoErr: domain.ErrUserNotFound, wantErr: domain.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockUserRepository)
+			mockRepo.On("GetByID", mock.Anything, "1").Return(tt.user, tt.repoErr)
+
+			u := usecase.NewAccountStatusUsecase(mockRepo, time.Second*2)
+			role, err := u.CheckActive(context.Background(), "1")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRole, role)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
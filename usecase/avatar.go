@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// AvatarSizes 为生成的头像缩略图边长（像素），最后一个为默认展示尺寸
+var AvatarSizes = []int{64, 128, 256}
+
+// maxAvatarPixels 限制解码前的图片像素数，防止解压炸弹耗尽内存
+const maxAvatarPixels = 4096 * 4096
+
+var allowedAvatarFormats = map[string]bool{
+	"jpeg": true,
+	"png":  true,
+	"gif":  true,
+	"webp": true,
+}
+
+// makeAvatarThumbnails 将图片居中裁剪为正方形并缩放到 AvatarSizes 中的各个尺寸，输出 PNG。
+// 解码前先按 DecodeConfig 检查像素数；解码和每次缩放后检查 ctx，超时即放弃剩余工作
+func makeAvatarThumbnails(ctx context.Context, data []byte) (map[int][]byte, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || !allowedAvatarFormats[format] {
+		return nil, domain.ErrInvalidImage
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxAvatarPixels {
+		return nil, domain.ErrInvalidImage
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ErrInvalidImage
+	}
+	src = cropSquare(src)
+
+	thumbnails := make(map[int][]byte, len(AvatarSizes))
+	for _, size := range AvatarSizes {
+		if err := ctx.Err(); err != nil {
+			return nil, avatarTimeoutError(err)
+		}
+		dst := image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, dst); err != nil {
+			return nil, err
+		}
+		thumbnails[size] = buf.Bytes()
+	}
+	return thumbnails, nil
+}
+
+// avatarTimeoutError 将超过 avatarTimeout 的错误转换为 domain.ErrTimeout（503），其余错误原样返回
+func avatarTimeoutError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, domain.ErrTimeout) {
+		return fmt.Errorf("%w: %w", domain.ErrTimeout, err)
+	}
+	return err
+}
+
+func cropSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	rect := image.Rect(x0, y0, x0+side, y0+side)
+
+	if sub, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
@@ -0,0 +1,27 @@
+package usecase_test
+
+import (
+	"context"
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBlobStore struct {
+	mock.Mock
+}
+
+func (m *MockBlobStore) Put(c context.Context, key string, data io.Reader, size int64, contentType string) error {
+	args := m.Called(c, key, data, size, contentType)
+	return args.Error(0)
+}
+
+func (m *MockBlobStore) Delete(c context.Context, key string) error {
+	args := m.Called(c, key)
+	return args.Error(0)
+}
+
+func (m *MockBlobStore) URL(key string) string {
+	args := m.Called(key)
+	return args.String(0)
+}
@@ -2,12 +2,17 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/horaoen/go-backend-clean-architecture/domain"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// dummyPasswordHash 用于用户不存在时执行一次等价的 bcrypt 比较，
+// 使登录耗时与用户是否存在无关，避免通过响应时间枚举已注册邮箱
+const dummyPasswordHash = "$2a$10$K4JOBbB2CC5u0kRsvzro6O3TIAGGLEQVlNnp6C80ICQJVSvXsQr4K"
+
 type loginUsecase struct {
 	userRepository domain.UserRepository
 	tokenService   domain.TokenService
@@ -27,13 +32,21 @@ func (lu *loginUsecase) Login(c context.Context, email, password string) (domain
 	defer cancel()
 
 	user, err := lu.userRepository.GetByEmail(ctx, email)
-	if err != nil {
+	if errors.Is(err, domain.ErrUserNotFound) {
+		_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(password))
 		return domain.TokenPair{}, domain.ErrInvalidCredentials
 	}
+	if err != nil {
+		return domain.TokenPair{}, err
+	}
 
 	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
 		return domain.TokenPair{}, domain.ErrInvalidCredentials
 	}
 
+	if user.DisabledAt != nil {
+		return domain.TokenPair{}, domain.ErrUserDisabled
+	}
+
 	return lu.tokenService.GenerateTokenPair(&user)
 }
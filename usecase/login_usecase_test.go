@@ -3,6 +3,7 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -27,6 +28,16 @@ func (m *MockTokenService) ExtractIDFromToken(token string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockTokenService) GenerateEmailChangeToken(user *domain.User, newEmail string) (string, error) {
+	args := m.Called(user, newEmail)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTokenService) ParseEmailChangeToken(token string) (string, string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.String(2), args.Error(3)
+}
+
 func TestLoginUsecase_Login(t *testing.T) {
 	email := "test@example.com"
 	password := "password"
@@ -63,7 +74,7 @@ func TestLoginUsecase_Login(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockTokenService := new(MockTokenService)
 
-		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, errors.New("not found"))
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, domain.ErrUserNotFound)
 
 		u := usecase.NewLoginUsecase(mockRepo, mockTokenService, time.Second*2)
 		_, err := u.Login(context.Background(), email, password)
@@ -73,6 +84,20 @@ func TestLoginUsecase_Login(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("repository_error", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, fmt.Errorf("%w: %w", domain.ErrTimeout, errors.New("i/o timeout")))
+
+		u := usecase.NewLoginUsecase(mockRepo, mockTokenService, time.Second*2)
+		_, err := u.Login(context.Background(), email, password)
+
+		assert.ErrorIs(t, err, domain.ErrTimeout)
+		assert.NotErrorIs(t, err, domain.ErrInvalidCredentials)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("invalid_password", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockTokenService := new(MockTokenService)
@@ -86,4 +111,20 @@ func TestLoginUsecase_Login(t *testing.T) {
 		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
 		mockRepo.AssertExpectations(t)
 	})
+	t.Run("disabled_user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+
+		disabledAt := time.Now()
+		disabledUser := user
+		disabledUser.DisabledAt = &disabledAt
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(disabledUser, nil)
+
+		u := usecase.NewLoginUsecase(mockRepo, mockTokenService, time.Second*2)
+		_, err := u.Login(context.Background(), email, password)
+
+		assert.ErrorIs(t, err, domain.ErrUserDisabled)
+		mockRepo.AssertExpectations(t)
+		mockTokenService.AssertNotCalled(t, "GenerateTokenPair", mock.Anything)
+	})
 }
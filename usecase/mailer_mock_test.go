@@ -0,0 +1,17 @@
+package usecase_test
+
+import (
+	"context"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(c context.Context, mail domain.Mail) error {
+	args := m.Called(c, mail)
+	return args.Error(0)
+}
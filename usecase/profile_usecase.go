@@ -1,23 +1,53 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/text/language"
 )
 
 type profileUsecase struct {
 	userRepository domain.UserRepository
+	txManager      domain.TxManager
+	tokenService   domain.TokenService
+	mailer         domain.Mailer
+	blobStore      domain.BlobStore
+	confirmURL     string
 	contextTimeout time.Duration
+	avatarTimeout  time.Duration
 }
 
-func NewProfileUsecase(userRepository domain.UserRepository, timeout time.Duration) domain.ProfileUsecase {
+// NewProfileUsecase 创建资料用例，confirmURL 为邮件中邮箱变更确认链接的地址，token 以查询参数附加在其后；
+// 修改资料的读-改-写在 txManager 的事务中执行并对记录加行锁，防止并发请求相互覆盖；
+// 头像上传需解码和缩放图片，使用单独的 avatarTimeout
+func NewProfileUsecase(
+	userRepository domain.UserRepository,
+	txManager domain.TxManager,
+	tokenService domain.TokenService,
+	mailer domain.Mailer,
+	blobStore domain.BlobStore,
+	confirmURL string,
+	timeout time.Duration,
+	avatarTimeout time.Duration,
+) domain.ProfileUsecase {
 	return &profileUsecase{
 		userRepository: userRepository,
+		txManager:      txManager,
+		tokenService:   tokenService,
+		mailer:         mailer,
+		blobStore:      blobStore,
+		confirmURL:     confirmURL,
 		contextTimeout: timeout,
+		avatarTimeout:  avatarTimeout,
 	}
 }
 
@@ -30,7 +60,7 @@ func (pu *profileUsecase) GetProfileByID(c context.Context, userID string) (*dom
 		return nil, err
 	}
 
-	return &domain.Profile{Name: user.Name, Email: user.Email}, nil
+	return pu.toProfile(&user), nil
 }
 
 func (pu *profileUsecase) ChangePassword(c context.Context, userID string, oldPassword string, newPassword string) error {
@@ -43,14 +73,276 @@ func (pu *profileUsecase) ChangePassword(c context.Context, userID string, oldPa
 	}
 
 	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)) != nil {
-		return errors.New("invalid old password")
+		return domain.ErrInvalidCredentials.WithMessage("invalid old password")
 	}
 
+	// 哈希计算较慢，放在事务外以免长时间持有行锁
 	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
-	user.Password = string(encryptedPassword)
-	return pu.userRepository.Update(ctx, &user)
+	return pu.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		locked, err := pu.userRepository.GetByIDForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		// 校验旧密码后密码已被并发修改，旧密码不再有效
+		if locked.Password != user.Password {
+			return domain.ErrInvalidCredentials.WithMessage("invalid old password")
+		}
+
+		locked.Password = string(encryptedPassword)
+		return pu.userRepository.Update(ctx, &locked)
+	})
+}
+
+func (pu *profileUsecase) DeleteAccount(c context.Context, userID string, password string) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return domain.ErrInvalidCredentials
+	}
+
+	return pu.userRepository.Delete(ctx, userID)
+}
+
+func (pu *profileUsecase) ExportData(c context.Context, userID string) (*domain.UserDataExport, error) {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserDataExport{
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		AvatarURL:  pu.avatarURL(&user),
+		Attributes: user.Attributes,
+		Role:       user.Role,
+		DisabledAt: user.DisabledAt,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+		ExportedAt: time.Now(),
+	}, nil
+}
+
+func (pu *profileUsecase) UpdateProfile(c context.Context, userID string, update domain.ProfileUpdate) (*domain.Profile, error) {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	var user domain.User
+	err := pu.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = pu.userRepository.GetByIDForUpdate(ctx, userID); err != nil {
+			return err
+		}
+
+		if update.Name != nil {
+			user.Name = *update.Name
+		}
+
+		// 属性补丁基于加锁后读到的最新值合并，不会丢失并发请求写入的其他属性
+		attributes := update.Attributes.Apply(user.Attributes)
+		if err := validateProfileAttributes(attributes); err != nil {
+			return err
+		}
+		user.Attributes = attributes
+
+		return pu.userRepository.Update(ctx, &user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pu.toProfile(&user), nil
+}
+
+func (pu *profileUsecase) RequestEmailChange(c context.Context, userID string, password string, newEmail string) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return domain.ErrInvalidCredentials
+	}
+
+	if err := pu.ensureEmailAvailable(ctx, newEmail); err != nil {
+		return err
+	}
+
+	token, err := pu.tokenService.GenerateEmailChangeToken(&user, newEmail)
+	if err != nil {
+		return err
+	}
+
+	confirmURL := pu.confirmURL + "?token=" + url.QueryEscape(token)
+	if err := pu.mailer.Send(ctx, domain.Mail{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body:    "Open the following link to confirm your new email address: " + confirmURL,
+	}); err != nil {
+		return err
+	}
+
+	// 确认邮件已经发出，此时返回错误会让客户端重试并重复发送确认邮件，通知失败只记录日志
+	if err := pu.mailer.Send(ctx, domain.Mail{
+		To:      user.Email,
+		Subject: "Email change requested",
+		Body:    "A request was made to change your account email to " + newEmail + ". If this wasn't you, change your password immediately.",
+	}); err != nil {
+		log.Ctx(ctx).Err(err).Str("userId", userID).Msg("旧邮箱变更通知发送失败")
+	}
+	return nil
+}
+
+func (pu *profileUsecase) ConfirmEmailChange(c context.Context, token string) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	userID, oldEmail, newEmail, err := pu.tokenService.ParseEmailChangeToken(token)
+	if err != nil {
+		return domain.ErrInvalidToken
+	}
+
+	return pu.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := pu.userRepository.GetByIDForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		// 签发后邮箱已变更过，旧 token 作废；加锁保证同一 token 并发确认时只有一次生效
+		if user.Email != oldEmail {
+			return domain.ErrInvalidToken
+		}
+
+		if err := pu.ensureEmailAvailable(ctx, newEmail); err != nil {
+			return err
+		}
+
+		user.Email = newEmail
+		return pu.userRepository.Update(ctx, &user)
+	})
+}
+
+func (pu *profileUsecase) UploadAvatar(c context.Context, userID string, image []byte) (*domain.Profile, error) {
+	ctx, cancel := context.WithTimeout(c, pu.avatarTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnails, err := makeAvatarThumbnails(ctx, image)
+	if err != nil {
+		return nil, err
+	}
+
+	// 每次上传使用新的前缀，避免 CDN/浏览器缓存旧头像
+	prefix := fmt.Sprintf("avatars/%s/%s", userID, strconv.FormatInt(time.Now().UnixNano(), 36))
+	for _, size := range AvatarSizes {
+		data := thumbnails[size]
+		if err := pu.blobStore.Put(ctx, avatarKey(prefix, size), bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+			pu.deleteAvatar(ctx, prefix)
+			return nil, avatarTimeoutError(err)
+		}
+	}
+
+	// 上传耗时较长，加锁后重新读取再写回，避免覆盖上传期间其他请求对资料的修改
+	var oldPrefix string
+	err = pu.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = pu.userRepository.GetByIDForUpdate(ctx, userID); err != nil {
+			return err
+		}
+		oldPrefix = user.Avatar
+		user.Avatar = prefix
+		return pu.userRepository.Update(ctx, &user)
+	})
+	if err != nil {
+		// 新头像未写入资料，删除已上传的文件
+		pu.deleteAvatar(ctx, prefix)
+		return nil, avatarTimeoutError(err)
+	}
+
+	if oldPrefix != "" {
+		pu.deleteAvatar(ctx, oldPrefix)
+	}
+
+	return pu.toProfile(&user), nil
+}
+
+// deleteAvatar 尽力删除 prefix 下的全部缩略图，失败只记录日志。
+// 不继承 ctx 的期限，头像处理超时后仍能完成清理
+func (pu *profileUsecase) deleteAvatar(ctx context.Context, prefix string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), pu.contextTimeout)
+	defer cancel()
+
+	for _, size := range AvatarSizes {
+		if err := pu.blobStore.Delete(ctx, avatarKey(prefix, size)); err != nil {
+			log.Ctx(ctx).Err(err).Str("key", avatarKey(prefix, size)).Msg("头像文件删除失败")
+		}
+	}
+}
+
+func (pu *profileUsecase) ensureEmailAvailable(ctx context.Context, email string) error {
+	_, err := pu.userRepository.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		return domain.ErrUserAlreadyExists
+	case errors.Is(err, domain.ErrUserNotFound):
+		return nil
+	}
+	return err
+}
+
+// validateProfileAttributes 在 ProfileAttributes.Validate 之外校验 Locale 为合法的 BCP 47 语言标签
+func validateProfileAttributes(attributes domain.ProfileAttributes) error {
+	if err := attributes.Validate(); err != nil {
+		return err
+	}
+	if attributes.Locale != "" {
+		if _, err := language.Parse(attributes.Locale); err != nil {
+			return domain.ErrInvalidProfileAttributes.WithFields(domain.FieldError{
+				Field:   "locale",
+				Rule:    "bcp47",
+				Message: "must be a BCP 47 language tag",
+			})
+		}
+	}
+	return nil
+}
+
+func (pu *profileUsecase) toProfile(user *domain.User) *domain.Profile {
+	return &domain.Profile{
+		Name:       user.Name,
+		Email:      user.Email,
+		AvatarURL:  pu.avatarURL(user),
+		Attributes: user.Attributes,
+	}
+}
+
+func (pu *profileUsecase) avatarURL(user *domain.User) string {
+	if user.Avatar == "" {
+		return ""
+	}
+	return pu.blobStore.URL(avatarKey(user.Avatar, AvatarSizes[len(AvatarSizes)-1]))
+}
+
+func avatarKey(prefix string, size int) string {
+	return fmt.Sprintf("%s/%d.png", prefix, size)
 }
@@ -1,8 +1,13 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +18,8 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const testConfirmURL = "https://app.example.com/api/v1/profile/email/confirm"
+
 func TestProfileUsecase_ChangePassword(t *testing.T) {
 	userID := "1"
 	oldPassword := "old_password"
@@ -27,12 +34,13 @@ func TestProfileUsecase_ChangePassword(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, userID).Return(user, nil)
 		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
 			err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(newPassword))
 			return u.ID == user.ID && err == nil
 		})).Return(nil)
 
-		pu := usecase.NewProfileUsecase(mockRepo, time.Second*2)
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
 		err := pu.ChangePassword(context.Background(), userID, oldPassword, newPassword)
 
 		assert.NoError(t, err)
@@ -43,7 +51,7 @@ func TestProfileUsecase_ChangePassword(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
 
-		pu := usecase.NewProfileUsecase(mockRepo, time.Second*2)
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
 		err := pu.ChangePassword(context.Background(), userID, "wrong_old_password", newPassword)
 
 		assert.Error(t, err)
@@ -51,14 +59,381 @@ func TestProfileUsecase_ChangePassword(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("changed_concurrently", func(t *testing.T) {
+		changed := user
+		changed.Password = "hash-written-by-another-request"
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, userID).Return(changed, nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.ChangePassword(context.Background(), userID, oldPassword, newPassword)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
 	t.Run("user_not_found", func(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockRepo.On("GetByID", mock.Anything, userID).Return(domain.User{}, errors.New("user not found"))
 
-		pu := usecase.NewProfileUsecase(mockRepo, time.Second*2)
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
 		err := pu.ChangePassword(context.Background(), userID, oldPassword, newPassword)
 
 		assert.Error(t, err)
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestProfileUsecase_DeleteAccount(t *testing.T) {
+	userID := "1"
+	password := "password"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	user := domain.User{
+		ID:       1,
+		Email:    "test@example.com",
+		Password: string(hashedPassword),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+		mockRepo.On("Delete", mock.Anything, userID).Return(nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.DeleteAccount(context.Background(), userID, password)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid_password", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.DeleteAccount(context.Background(), userID, "wrong_password")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProfileUsecase_ExportData(t *testing.T) {
+	disabledAt := time.Now()
+	user := domain.User{
+		ID:         1,
+		Name:       "Test User",
+		Email:      "test@example.com",
+		Password:   "hashed",
+		Role:       domain.RoleAdmin,
+		DisabledAt: &disabledAt,
+	}
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByID", mock.Anything, "1").Return(user, nil)
+
+	pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+	export, err := pu.ExportData(context.Background(), "1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, export.ID)
+	assert.Equal(t, user.Name, export.Name)
+	assert.Equal(t, user.Email, export.Email)
+	assert.Equal(t, user.Role, export.Role)
+	assert.Equal(t, user.DisabledAt, export.DisabledAt)
+	assert.False(t, export.ExportedAt.IsZero())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProfileUsecase_UpdateProfile(t *testing.T) {
+	user := domain.User{
+		ID:    1,
+		Name:  "Old Name",
+		Email: "test@example.com",
+	}
+	newName := "New Name"
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByIDForUpdate", mock.Anything, "1").Return(user, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Name == newName && u.Email == user.Email
+	})).Return(nil)
+
+	pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+	profile, err := pu.UpdateProfile(context.Background(), "1", domain.ProfileUpdate{Name: &newName})
+
+	assert.NoError(t, err)
+	assert.Equal(t, newName, profile.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProfileUsecase_UpdateProfile_Attributes(t *testing.T) {
+	user := domain.User{
+		ID:         1,
+		Name:       "Test User",
+		Email:      "test@example.com",
+		Attributes: domain.ProfileAttributes{Locale: "en", Metadata: map[string]any{"theme": "dark"}},
+	}
+
+	t.Run("partial_update", func(t *testing.T) {
+		timezone := "Asia/Shanghai"
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, "1").Return(user, nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Name == user.Name &&
+				u.Attributes.Locale == "en" &&
+				u.Attributes.Timezone == timezone &&
+				u.Attributes.Metadata["theme"] == "dark" &&
+				u.Attributes.Metadata["beta"] == true
+		})).Return(nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		profile, err := pu.UpdateProfile(context.Background(), "1", domain.ProfileUpdate{
+			Attributes: domain.ProfileAttributesUpdate{
+				Timezone: &timezone,
+				Metadata: map[string]any{"beta": true},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, timezone, profile.Attributes.Timezone)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid_attributes", func(t *testing.T) {
+		phone := "12345"
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, "1").Return(user, nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		_, err := pu.UpdateProfile(context.Background(), "1", domain.ProfileUpdate{
+			Attributes: domain.ProfileAttributesUpdate{Phone: &phone},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidProfileAttributes)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid_locale", func(t *testing.T) {
+		locale := "not a locale"
+
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, "1").Return(user, nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		_, err := pu.UpdateProfile(context.Background(), "1", domain.ProfileUpdate{
+			Attributes: domain.ProfileAttributesUpdate{Locale: &locale},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidProfileAttributes)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestProfileUsecase_RequestEmailChange(t *testing.T) {
+	userID := "1"
+	password := "password"
+	newEmail := "new@example.com"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	user := domain.User{
+		ID:       1,
+		Email:    "old@example.com",
+		Password: string(hashedPassword),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+		mockMailer := new(MockMailer)
+
+		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+		mockRepo.On("GetByEmail", mock.Anything, newEmail).Return(domain.User{}, domain.ErrUserNotFound)
+		mockTokenService.On("GenerateEmailChangeToken", mock.Anything, newEmail).Return("change_token", nil)
+		mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(m domain.Mail) bool {
+			return m.To == newEmail && strings.Contains(m.Body, testConfirmURL+"?token=change_token")
+		})).Return(nil)
+		mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(m domain.Mail) bool {
+			return m.To == user.Email && strings.Contains(m.Body, newEmail)
+		})).Return(nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, mockTokenService, mockMailer, new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.RequestEmailChange(context.Background(), userID, password, newEmail)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockTokenService.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("old_address_notice_failed", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+		mockMailer := new(MockMailer)
+
+		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+		mockRepo.On("GetByEmail", mock.Anything, newEmail).Return(domain.User{}, domain.ErrUserNotFound)
+		mockTokenService.On("GenerateEmailChangeToken", mock.Anything, newEmail).Return("change_token", nil)
+		mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(m domain.Mail) bool { return m.To == newEmail })).Return(nil)
+		mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(m domain.Mail) bool { return m.To == user.Email })).Return(errors.New("smtp unavailable"))
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, mockTokenService, mockMailer, new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.RequestEmailChange(context.Background(), userID, password, newEmail)
+
+		assert.NoError(t, err, "confirmation mail already went out")
+		mockMailer.AssertExpectations(t)
+	})
+
+	t.Run("email_taken", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+		mockRepo.On("GetByEmail", mock.Anything, newEmail).Return(domain.User{ID: 2, Email: newEmail}, nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.RequestEmailChange(context.Background(), userID, password, newEmail)
+
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+	})
+
+	t.Run("invalid_password", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, userID).Return(user, nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.RequestEmailChange(context.Background(), userID, "wrong_password", newEmail)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	})
+}
+
+func TestProfileUsecase_ConfirmEmailChange(t *testing.T) {
+	user := domain.User{
+		ID:    1,
+		Email: "old@example.com",
+	}
+	newEmail := "new@example.com"
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+
+		mockTokenService.On("ParseEmailChangeToken", "change_token").Return("1", user.Email, newEmail, nil)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, "1").Return(user, nil)
+		mockRepo.On("GetByEmail", mock.Anything, newEmail).Return(domain.User{}, domain.ErrUserNotFound)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == newEmail
+		})).Return(nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, mockTokenService, new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.ConfirmEmailChange(context.Background(), "change_token")
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("stale_token", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+
+		mockTokenService.On("ParseEmailChangeToken", "change_token").Return("1", "older@example.com", newEmail, nil)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, "1").Return(user, nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, mockTokenService, new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.ConfirmEmailChange(context.Background(), "change_token")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid_token", func(t *testing.T) {
+		mockTokenService := new(MockTokenService)
+		mockTokenService.On("ParseEmailChangeToken", "bad").Return("", "", "", errors.New("bad token"))
+
+		pu := usecase.NewProfileUsecase(new(MockUserRepository), MockTxManager{}, mockTokenService, new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		err := pu.ConfirmEmailChange(context.Background(), "bad")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidToken)
+	})
+}
+
+func TestProfileUsecase_UploadAvatar(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+
+	t.Run("success", func(t *testing.T) {
+		user := domain.User{ID: 1, Name: "Test User", Email: "test@example.com", Avatar: "avatars/1/old"}
+
+		mockRepo := new(MockUserRepository)
+		mockBlobStore := new(MockBlobStore)
+
+		mockRepo.On("GetByID", mock.Anything, "1").Return(user, nil)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, "1").Return(user, nil)
+		for _, size := range usecase.AvatarSizes {
+			suffix := fmt.Sprintf("/%d.png", size)
+			mockBlobStore.On("Put", mock.Anything, mock.MatchedBy(func(key string) bool {
+				return strings.HasPrefix(key, "avatars/1/") && strings.HasSuffix(key, suffix) && !strings.HasPrefix(key, "avatars/1/old/")
+			}), mock.Anything, mock.Anything, "image/png").Return(nil).Once()
+			mockBlobStore.On("Delete", mock.Anything, "avatars/1/old"+suffix).Return(nil).Once()
+		}
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return strings.HasPrefix(u.Avatar, "avatars/1/") && u.Avatar != "avatars/1/old"
+		})).Return(nil)
+		mockBlobStore.On("URL", mock.Anything).Return("http://cdn/avatar.png")
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), mockBlobStore, testConfirmURL, time.Second*2, time.Second*10)
+		profile, err := pu.UploadAvatar(context.Background(), "1", buf.Bytes())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "http://cdn/avatar.png", profile.AvatarURL)
+		mockRepo.AssertExpectations(t)
+		mockBlobStore.AssertExpectations(t)
+	})
+
+	t.Run("invalid_image", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, "1").Return(domain.User{ID: 1}, nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), new(MockBlobStore), testConfirmURL, time.Second*2, time.Second*10)
+		_, err := pu.UploadAvatar(context.Background(), "1", []byte("not an image"))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidImage)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("transaction_failed", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockBlobStore := new(MockBlobStore)
+		mockRepo.On("GetByID", mock.Anything, "1").Return(domain.User{ID: 1}, nil)
+		mockRepo.On("GetByIDForUpdate", mock.Anything, "1").Return(domain.User{}, domain.ErrUserNotFound)
+		var uploaded []string
+		mockBlobStore.On("Put", mock.Anything, mock.AnythingOfType("string"), mock.Anything, mock.Anything, "image/png").
+			Run(func(args mock.Arguments) { uploaded = append(uploaded, args.String(1)) }).Return(nil)
+		var deleted []string
+		mockBlobStore.On("Delete", mock.Anything, mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) { deleted = append(deleted, args.String(1)) }).Return(nil)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), mockBlobStore, testConfirmURL, time.Second*2, time.Second*10)
+		_, err := pu.UploadAvatar(context.Background(), "1", buf.Bytes())
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+		assert.Len(t, uploaded, len(usecase.AvatarSizes))
+		assert.ElementsMatch(t, uploaded, deleted, "uploaded thumbnails are removed when the profile is not updated")
+	})
+
+	t.Run("avatar_timeout", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByID", mock.Anything, "1").Return(domain.User{ID: 1}, nil)
+		mockBlobStore := new(MockBlobStore)
+
+		pu := usecase.NewProfileUsecase(mockRepo, MockTxManager{}, new(MockTokenService), new(MockMailer), mockBlobStore, testConfirmURL, time.Second*2, time.Nanosecond)
+		_, err := pu.UploadAvatar(context.Background(), "1", buf.Bytes())
+
+		assert.ErrorIs(t, err, domain.ErrTimeout)
+		mockBlobStore.AssertNotCalled(t, "Put", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
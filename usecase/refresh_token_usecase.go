@@ -32,7 +32,11 @@ func (rtu *refreshTokenUsecase) Refresh(c context.Context, refreshToken string)
 
 	user, err := rtu.userRepository.GetByID(ctx, userID)
 	if err != nil {
-		return domain.TokenPair{}, domain.ErrUserNotFound
+		return domain.TokenPair{}, err
+	}
+
+	if user.DisabledAt != nil {
+		return domain.TokenPair{}, domain.ErrUserDisabled
 	}
 
 	return rtu.tokenService.GenerateTokenPair(&user)
@@ -62,7 +62,7 @@ func TestRefreshTokenUsecase_Refresh(t *testing.T) {
 		mockTokenService := new(MockTokenService)
 
 		mockTokenService.On("ExtractIDFromToken", refreshToken).Return(userID, nil)
-		mockRepo.On("GetByID", mock.Anything, userID).Return(domain.User{}, errors.New("not found"))
+		mockRepo.On("GetByID", mock.Anything, userID).Return(domain.User{}, domain.ErrUserNotFound)
 
 		u := usecase.NewRefreshTokenUsecase(mockRepo, mockTokenService, time.Second*2)
 		_, err := u.Refresh(context.Background(), refreshToken)
@@ -2,23 +2,37 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type signupUsecase struct {
-	userRepository domain.UserRepository
-	tokenService   domain.TokenService
-	contextTimeout time.Duration
+	userRepository  domain.UserRepository
+	tokenService    domain.TokenService
+	mailer          domain.Mailer
+	concealExisting bool
+	contextTimeout  time.Duration
 }
 
-func NewSignupUsecase(userRepository domain.UserRepository, tokenService domain.TokenService, timeout time.Duration) domain.SignupUsecase {
+// NewSignupUsecase 创建注册用例。concealExisting 为 true 时注册请求总是被受理，
+// 不签发 token，而是通过邮件告知邮箱所有者结果，避免泄露邮箱是否已注册。
+func NewSignupUsecase(
+	userRepository domain.UserRepository,
+	tokenService domain.TokenService,
+	mailer domain.Mailer,
+	concealExisting bool,
+	timeout time.Duration,
+) domain.SignupUsecase {
 	return &signupUsecase{
-		userRepository: userRepository,
-		tokenService:   tokenService,
-		contextTimeout: timeout,
+		userRepository:  userRepository,
+		tokenService:    tokenService,
+		mailer:          mailer,
+		concealExisting: concealExisting,
+		contextTimeout:  timeout,
 	}
 }
 
@@ -26,25 +40,63 @@ func (su *signupUsecase) Signup(c context.Context, name, email, password string)
 	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
 	defer cancel()
 
-	_, err := su.userRepository.GetByEmail(ctx, email)
-	if err == nil {
-		return domain.TokenPair{}, domain.ErrUserAlreadyExists
-	}
-
+	// 先计算哈希，保证无论邮箱是否已注册，请求耗时都包含一次 bcrypt
 	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return domain.TokenPair{}, domain.ErrInternalServer
 	}
 
+	_, err = su.userRepository.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		return su.existing(ctx, email)
+	case !errors.Is(err, domain.ErrUserNotFound):
+		return domain.TokenPair{}, domain.ErrInternalServer
+	}
+
 	user := domain.User{
 		Name:     name,
 		Email:    email,
 		Password: string(encryptedPassword),
+		Role:     domain.RoleUser,
 	}
 
 	if err := su.userRepository.Create(ctx, &user); err != nil {
+		// 并发注册同一邮箱时由唯一索引兜底
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			return su.existing(ctx, email)
+		}
 		return domain.TokenPair{}, domain.ErrInternalServer
 	}
 
+	if su.concealExisting {
+		su.notify(ctx, domain.Mail{
+			To:      email,
+			Subject: "Welcome",
+			Body:    "Your account has been created. You can now log in.",
+		})
+		return domain.TokenPair{}, nil
+	}
+
 	return su.tokenService.GenerateTokenPair(&user)
 }
+
+// existing 处理邮箱已注册的情况，隐藏模式下与注册成功的响应一致
+func (su *signupUsecase) existing(ctx context.Context, email string) (domain.TokenPair, error) {
+	if !su.concealExisting {
+		return domain.TokenPair{}, domain.ErrUserAlreadyExists
+	}
+	su.notify(ctx, domain.Mail{
+		To:      email,
+		Subject: "Sign up attempt",
+		Body:    "Someone tried to create an account with this email address. If this was you, you can log in or reset your password.",
+	})
+	return domain.TokenPair{}, nil
+}
+
+// notify 发送邮件失败只记录日志，不影响响应，以免通过错误差异泄露账号状态
+func (su *signupUsecase) notify(ctx context.Context, mail domain.Mail) {
+	if err := su.mailer.Send(ctx, mail); err != nil {
+		log.Ctx(ctx).Err(err).Str("to", mail.To).Msg("signup mail send failed")
+	}
+}
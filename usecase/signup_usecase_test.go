@@ -26,11 +26,11 @@ func TestSignupUsecase_Signup(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockTokenService := new(MockTokenService)
 
-		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, errors.New("not found"))
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, domain.ErrUserNotFound)
 		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
 		mockTokenService.On("GenerateTokenPair", mock.Anything).Return(expectedTokens, nil)
 
-		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, time.Second*2)
+		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, new(MockMailer), false, time.Second*2)
 		tokens, err := u.Signup(context.Background(), name, email, password)
 
 		assert.NoError(t, err)
@@ -46,7 +46,7 @@ func TestSignupUsecase_Signup(t *testing.T) {
 		existingUser := domain.User{Email: email}
 		mockRepo.On("GetByEmail", mock.Anything, email).Return(existingUser, nil)
 
-		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, time.Second*2)
+		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, new(MockMailer), false, time.Second*2)
 		_, err := u.Signup(context.Background(), name, email, password)
 
 		assert.Error(t, err)
@@ -58,14 +58,70 @@ func TestSignupUsecase_Signup(t *testing.T) {
 		mockRepo := new(MockUserRepository)
 		mockTokenService := new(MockTokenService)
 
-		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, errors.New("not found"))
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, domain.ErrUserNotFound)
 		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("database error"))
 
-		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, time.Second*2)
+		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, new(MockMailer), false, time.Second*2)
 		_, err := u.Signup(context.Background(), name, email, password)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, domain.ErrInternalServer)
 		mockRepo.AssertExpectations(t)
 	})
+	t.Run("create_conflict", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+
+		// 并发注册：查重时邮箱尚未存在，写入时触发唯一索引
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, domain.ErrUserNotFound)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(domain.ErrUserAlreadyExists)
+
+		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, new(MockMailer), false, time.Second*2)
+		_, err := u.Signup(context.Background(), name, email, password)
+
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+		mockRepo.AssertExpectations(t)
+		mockTokenService.AssertNotCalled(t, "GenerateTokenPair", mock.Anything)
+	})
+
+	t.Run("conceal_existing_user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+		mockMailer := new(MockMailer)
+
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{Email: email}, nil)
+		mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(m domain.Mail) bool {
+			return m.To == email && m.Subject == "Sign up attempt"
+		})).Return(nil)
+
+		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, mockMailer, true, time.Second*2)
+		tokens, err := u.Signup(context.Background(), name, email, password)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TokenPair{}, tokens)
+		mockRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+		mockTokenService.AssertNotCalled(t, "GenerateTokenPair", mock.Anything)
+	})
+
+	t.Run("conceal_new_user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockTokenService := new(MockTokenService)
+		mockMailer := new(MockMailer)
+
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, domain.ErrUserNotFound)
+		mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+		mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(m domain.Mail) bool {
+			return m.To == email && m.Subject == "Welcome"
+		})).Return(errors.New("smtp down"))
+
+		u := usecase.NewSignupUsecase(mockRepo, mockTokenService, mockMailer, true, time.Second*2)
+		tokens, err := u.Signup(context.Background(), name, email, password)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.TokenPair{}, tokens)
+		mockRepo.AssertExpectations(t)
+		mockMailer.AssertExpectations(t)
+		mockTokenService.AssertNotCalled(t, "GenerateTokenPair", mock.Anything)
+	})
 }
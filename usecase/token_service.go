@@ -8,24 +8,33 @@ import (
 	"github.com/horaoen/go-backend-clean-architecture/domain"
 )
 
+// TokenLifetimes 为各类 token 的有效期（小时）
+type TokenLifetimes struct {
+	AccessTokenExpiryHour  int
+	RefreshTokenExpiryHour int
+	EmailTokenExpiryHour   int
+}
+
 type tokenService struct {
-	accessTokenSecret      string
-	refreshTokenSecret     string
-	accessTokenExpiryHour  int
-	refreshTokenExpiryHour int
+	accessTokenSecret  domain.Secret
+	refreshTokenSecret domain.Secret
+	emailTokenSecret   domain.Secret
+	lifetimes          func() TokenLifetimes
 }
 
+// NewTokenService 创建 token 服务。密钥和有效期每次签发时读取，轮换或热更新后立即生效，
+// 轮换前签发的 token 仍可用上一个密钥验证
 func NewTokenService(
-	accessTokenSecret string,
-	refreshTokenSecret string,
-	accessTokenExpiryHour int,
-	refreshTokenExpiryHour int,
+	accessTokenSecret domain.Secret,
+	refreshTokenSecret domain.Secret,
+	emailTokenSecret domain.Secret,
+	lifetimes func() TokenLifetimes,
 ) domain.TokenService {
 	return &tokenService{
-		accessTokenSecret:      accessTokenSecret,
-		refreshTokenSecret:     refreshTokenSecret,
-		accessTokenExpiryHour:  accessTokenExpiryHour,
-		refreshTokenExpiryHour: refreshTokenExpiryHour,
+		accessTokenSecret:  accessTokenSecret,
+		refreshTokenSecret: refreshTokenSecret,
+		emailTokenSecret:   emailTokenSecret,
+		lifetimes:          lifetimes,
 	}
 }
 
@@ -48,36 +57,53 @@ func (ts *tokenService) GenerateTokenPair(user *domain.User) (domain.TokenPair,
 
 func (ts *tokenService) ExtractIDFromToken(requestToken string) (string, error) {
 	claims := &domain.JwtCustomRefreshClaims{}
-	token, err := jwt.ParseWithClaims(requestToken, claims, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, domain.ErrInvalidToken
-		}
-		return []byte(ts.refreshTokenSecret), nil
-	})
-	if err != nil {
+	if err := parseWithSecret(requestToken, claims, ts.refreshTokenSecret); err != nil {
 		return "", err
 	}
-	if !token.Valid {
-		return "", domain.ErrInvalidToken
-	}
 	return claims.ID, nil
 }
 
+func (ts *tokenService) GenerateEmailChangeToken(user *domain.User, newEmail string) (string, error) {
+	exp := time.Now().Add(time.Hour * time.Duration(ts.lifetimes().EmailTokenExpiryHour))
+	claims := &domain.JwtEmailChangeClaims{
+		ID:       strconv.FormatUint(uint64(user.ID), 10),
+		OldEmail: user.Email,
+		NewEmail: newEmail,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(ts.emailTokenSecret.Current()))
+}
+
+func (ts *tokenService) ParseEmailChangeToken(requestToken string) (string, string, string, error) {
+	claims := &domain.JwtEmailChangeClaims{}
+	if err := parseWithSecret(requestToken, claims, ts.emailTokenSecret); err != nil {
+		return "", "", "", err
+	}
+	if claims.ID == "" || claims.NewEmail == "" {
+		return "", "", "", domain.ErrInvalidToken
+	}
+	return claims.ID, claims.OldEmail, claims.NewEmail, nil
+}
+
 func (ts *tokenService) createAccessToken(user *domain.User) (string, error) {
-	exp := time.Now().Add(time.Hour * time.Duration(ts.accessTokenExpiryHour))
+	exp := time.Now().Add(time.Hour * time.Duration(ts.lifetimes().AccessTokenExpiryHour))
 	claims := &domain.JwtCustomClaims{
 		Name: user.Name,
 		ID:   strconv.FormatUint(uint64(user.ID), 10),
+		Role: user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(exp),
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(ts.accessTokenSecret))
+	return token.SignedString([]byte(ts.accessTokenSecret.Current()))
 }
 
 func (ts *tokenService) createRefreshToken(user *domain.User) (string, error) {
-	exp := time.Now().Add(time.Hour * time.Duration(ts.refreshTokenExpiryHour))
+	exp := time.Now().Add(time.Hour * time.Duration(ts.lifetimes().RefreshTokenExpiryHour))
 	claims := &domain.JwtCustomRefreshClaims{
 		ID: strconv.FormatUint(uint64(user.ID), 10),
 		RegisteredClaims: jwt.RegisteredClaims{
@@ -85,5 +111,26 @@ func (ts *tokenService) createRefreshToken(user *domain.User) (string, error) {
 		},
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(ts.refreshTokenSecret))
+	return token.SignedString([]byte(ts.refreshTokenSecret.Current()))
+}
+
+// parseWithSecret 依次用 secret 的候选密钥验证 token，全部失败时返回最后一次的错误
+func parseWithSecret(requestToken string, claims jwt.Claims, secret domain.Secret) error {
+	var err error = domain.ErrInvalidToken
+	for _, key := range secret.Candidates() {
+		var token *jwt.Token
+		token, err = jwt.ParseWithClaims(requestToken, claims, func(token *jwt.Token) (any, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, domain.ErrInvalidToken
+			}
+			return []byte(key), nil
+		})
+		if err == nil {
+			if !token.Valid {
+				return domain.ErrInvalidToken
+			}
+			return nil
+		}
+	}
+	return err
 }
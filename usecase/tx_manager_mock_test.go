@@ -0,0 +1,10 @@
+package usecase_test
+
+import "context"
+
+// MockTxManager 直接执行 fn，事务语义由仓储层测试覆盖
+type MockTxManager struct{}
+
+func (MockTxManager) WithinTransaction(c context.Context, fn func(c context.Context) error) error {
+	return fn(c)
+}
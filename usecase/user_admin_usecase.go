@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type userAdminUsecase struct {
+	userRepository domain.UserRepository
+	txManager      domain.TxManager
+	contextTimeout time.Duration
+}
+
+// NewUserAdminUsecase 创建用户管理用例，先查询后修改的操作在 txManager 的事务中执行并对记录加行锁
+func NewUserAdminUsecase(userRepository domain.UserRepository, txManager domain.TxManager, timeout time.Duration) domain.UserAdminUsecase {
+	return &userAdminUsecase{
+		userRepository: userRepository,
+		txManager:      txManager,
+		contextTimeout: timeout,
+	}
+}
+
+func (uau *userAdminUsecase) CreateUser(c context.Context, name, email, password, role string) (domain.User, error) {
+	ctx, cancel := context.WithTimeout(c, uau.contextTimeout)
+	defer cancel()
+
+	if !domain.IsValidRole(role) {
+		return domain.User{}, domain.ErrInvalidRole
+	}
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user := domain.User{
+		Name:     name,
+		Email:    email,
+		Password: string(encryptedPassword),
+		Role:     role,
+	}
+	err = uau.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := uau.userRepository.GetByEmail(ctx, email)
+		switch {
+		case err == nil:
+			return domain.ErrUserAlreadyExists
+		case !errors.Is(err, domain.ErrUserNotFound):
+			return err
+		}
+		return uau.userRepository.Create(ctx, &user)
+	})
+	if err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+func (uau *userAdminUsecase) DisableUser(c context.Context, email string) error {
+	ctx, cancel := context.WithTimeout(c, uau.contextTimeout)
+	defer cancel()
+
+	return uau.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := uau.userRepository.GetByEmailForUpdate(ctx, email)
+		if err != nil {
+			return err
+		}
+		if user.DisabledAt != nil {
+			return nil
+		}
+
+		now := time.Now()
+		user.DisabledAt = &now
+		return uau.userRepository.Update(ctx, &user)
+	})
+}
+
+func (uau *userAdminUsecase) SetRole(c context.Context, email, role string) error {
+	ctx, cancel := context.WithTimeout(c, uau.contextTimeout)
+	defer cancel()
+
+	if !domain.IsValidRole(role) {
+		return domain.ErrInvalidRole
+	}
+	return uau.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := uau.userRepository.GetByEmailForUpdate(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		user.Role = role
+		return uau.userRepository.Update(ctx, &user)
+	})
+}
+
+func (uau *userAdminUsecase) ResetPassword(c context.Context, email, newPassword string) error {
+	ctx, cancel := context.WithTimeout(c, uau.contextTimeout)
+	defer cancel()
+
+	// 哈希计算较慢，放在事务外以免长时间占用连接
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return uau.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := uau.userRepository.GetByEmailForUpdate(ctx, email)
+		if err != nil {
+			return err
+		}
+
+		user.Password = string(encryptedPassword)
+		return uau.userRepository.Update(ctx, &user)
+	})
+}
@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/horaoen/go-backend-clean-architecture/domain"
+	"github.com/horaoen/go-backend-clean-architecture/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestUserAdminUsecase_CreateUser(t *testing.T) {
+	email := "admin@example.com"
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, domain.ErrUserNotFound)
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.Email == email && u.Role == domain.RoleAdmin &&
+				bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("secret123")) == nil
+		})).Return(nil)
+
+		u := usecase.NewUserAdminUsecase(mockRepo, MockTxManager{}, time.Second*2)
+		user, err := u.CreateUser(context.Background(), "Admin", email, "secret123", domain.RoleAdmin)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleAdmin, user.Role)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid_role", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+
+		u := usecase.NewUserAdminUsecase(mockRepo, MockTxManager{}, time.Second*2)
+		_, err := u.CreateUser(context.Background(), "Admin", email, "secret123", "root")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRole)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("already_exists", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{ID: 1, Email: email}, nil)
+
+		u := usecase.NewUserAdminUsecase(mockRepo, MockTxManager{}, time.Second*2)
+		_, err := u.CreateUser(context.Background(), "Admin", email, "secret123", domain.RoleUser)
+
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("lookup_error", func(t *testing.T) {
+		dbErr := errors.New("connection refused")
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmail", mock.Anything, email).Return(domain.User{}, dbErr)
+
+		u := usecase.NewUserAdminUsecase(mockRepo, MockTxManager{}, time.Second*2)
+		_, err := u.CreateUser(context.Background(), "Admin", email, "secret123", domain.RoleUser)
+
+		assert.ErrorIs(t, err, dbErr)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserAdminUsecase_DisableUser(t *testing.T) {
+	email := "test@example.com"
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmailForUpdate", mock.Anything, email).Return(domain.User{ID: 1, Email: email}, nil)
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+			return u.DisabledAt != nil
+		})).Return(nil)
+
+		u := usecase.NewUserAdminUsecase(mockRepo, MockTxManager{}, time.Second*2)
+		err := u.DisableUser(context.Background(), email)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("user_not_found", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetByEmailForUpdate", mock.Anything, email).Return(domain.User{}, domain.ErrUserNotFound)
+
+		u := usecase.NewUserAdminUsecase(mockRepo, MockTxManager{}, time.Second*2)
+		err := u.DisableUser(context.Background(), email)
+
+		assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	})
+}
+
+func TestUserAdminUsecase_SetRole(t *testing.T) {
+	email := "test@example.com"
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmailForUpdate", mock.Anything, email).Return(domain.User{ID: 1, Email: email, Role: domain.RoleUser}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Role == domain.RoleAdmin
+	})).Return(nil)
+
+	u := usecase.NewUserAdminUsecase(mockRepo, MockTxManager{}, time.Second*2)
+
+	assert.ErrorIs(t, u.SetRole(context.Background(), email, "root"), domain.ErrInvalidRole)
+	assert.NoError(t, u.SetRole(context.Background(), email, domain.RoleAdmin))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUserAdminUsecase_ResetPassword(t *testing.T) {
+	email := "test@example.com"
+
+	mockRepo := new(MockUserRepository)
+	mockRepo.On("GetByEmailForUpdate", mock.Anything, email).Return(domain.User{ID: 1, Email: email, Password: "old"}, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("newpassword")) == nil
+	})).Return(nil)
+
+	u := usecase.NewUserAdminUsecase(mockRepo, MockTxManager{}, time.Second*2)
+	err := u.ResetPassword(context.Background(), email, "newpassword")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -2,6 +2,7 @@ package usecase_test
 
 import (
 	"context"
+	"time"
 
 	"github.com/horaoen/go-backend-clean-architecture/domain"
 	"github.com/stretchr/testify/mock"
@@ -31,7 +32,37 @@ func (m *MockUserRepository) GetByID(c context.Context, id string) (domain.User,
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) GetByEmailForUpdate(c context.Context, email string) (domain.User, error) {
+	args := m.Called(c, email)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) GetByIDForUpdate(c context.Context, id string) (domain.User, error) {
+	args := m.Called(c, id)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
 func (m *MockUserRepository) Update(c context.Context, user *domain.User) error {
 	args := m.Called(c, user)
 	return args.Error(0)
 }
+
+func (m *MockUserRepository) Delete(c context.Context, id string) error {
+	args := m.Called(c, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FetchPurgeable(c context.Context, before time.Time, limit int) ([]domain.User, error) {
+	args := m.Called(c, before, limit)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Purge(c context.Context, id string) error {
+	args := m.Called(c, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Anonymize(c context.Context, id string) error {
+	args := m.Called(c, id)
+	return args.Error(0)
+}